@@ -17,11 +17,15 @@ import (
 	"SpaceBookProject/internal/handlers"
 	"SpaceBookProject/internal/repository"
 	"SpaceBookProject/internal/services"
+	"SpaceBookProject/internal/storage"
 	"SpaceBookProject/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
+// Путь, по которому API отдаёт файлы локального хранилища
+const uploadsPath = "/uploads"
+
 func main() {
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -39,22 +43,69 @@ func main() {
 	userRepo := repository.NewUserRepository(database)
 	bookingRepo := repository.NewBookingRepository(database)
 	spaceRepo := repository.NewSpaceRepository(database)
+	geoQuery, err := repository.NewGeoQuery(cfg.Search.GeoBackend)
+	if err != nil {
+		log.Fatalf("failed to init geo search: %v", err)
+	}
+	spaceRepo.UseGeo(geoQuery)
+	spaceRepo.UseTextSearchConfig(cfg.Search.TextConfig)
 	historyRepo := repository.NewBookingHistoryRepository(database)
+	pricingRuleRepo := repository.NewPricingRuleRepository(database)
+	reviewRepo := repository.NewReviewRepository(database)
+	messageRepo := repository.NewMessageRepository(database)
+	photoRepo := repository.NewSpacePhotoRepository(database)
+	amenityRepo := repository.NewAmenityRepository(database)
+	favoritesRepo := repository.NewFavoritesRepository(database)
+	savedSearchRepo := repository.NewSavedSearchRepository(database)
+	outboxRepo := repository.NewOutboxRepository(database)
+	notificationOutboxRepo := repository.NewNotificationOutboxRepository(database)
 
-	eventsChan := make(chan domain.BookingEvent, 100)
+	var store storage.Storage
+	switch cfg.Storage.Driver {
+	case "local":
+		publicURL := cfg.Storage.PublicURL
+		if publicURL == "" {
+			publicURL = uploadsPath
+		}
+		store = storage.NewLocalStorage(cfg.Storage.LocalDir, publicURL)
+	case "s3":
+		store = storage.NewS3Storage(storage.S3Config{
+			Endpoint:  cfg.Storage.S3Endpoint,
+			Region:    cfg.Storage.S3Region,
+			Bucket:    cfg.Storage.S3Bucket,
+			AccessKey: cfg.Storage.S3AccessKey,
+			SecretKey: cfg.Storage.S3SecretKey,
+			PublicURL: cfg.Storage.PublicURL,
+		})
+	default:
+		log.Fatalf("%v: %s", storage.ErrUnknownDriver, cfg.Storage.Driver)
+	}
 
 	authService := services.NewAuthService(userRepo, jwtManager)
-	bookingService := services.NewBookingService(bookingRepo, spaceRepo, historyRepo, eventsChan)
-	spaceService := services.NewSpaceService(spaceRepo)
+	bookingService := services.NewBookingService(bookingRepo, spaceRepo, pricingRuleRepo, historyRepo)
+	priceAlertService := services.NewPriceAlertService(favoritesRepo)
+	spaceService := services.NewSpaceService(spaceRepo, pricingRuleRepo, photoRepo, amenityRepo, priceAlertService, store, cfg.Storage.MaxPhotoBytes)
+	messageService := services.NewMessageService(messageRepo, bookingRepo, spaceRepo)
+	favoritesService := services.NewFavoritesService(favoritesRepo, spaceService)
+	savedSearchService := services.NewSavedSearchService(savedSearchRepo, spaceRepo)
+	reviewService := services.NewReviewService(reviewRepo, bookingRepo, spaceRepo, userRepo, cfg.Booking.ReviewWindow)
 
 	authHandler := handlers.NewAuthHandler(authService)
 	bookingHandler := handlers.NewBookingHandler(bookingService)
 	spaceHandler := handlers.NewSpaceHandler(spaceService)
+	reviewHandler := handlers.NewReviewHandler(reviewService)
+	messageHandler := handlers.NewMessageHandler(messageService)
+	favoritesHandler := handlers.NewFavoritesHandler(favoritesService, priceAlertService)
+	savedSearchHandler := handlers.NewSavedSearchHandler(savedSearchService)
 
 	gin.SetMode(cfg.Server.Mode)
 	r := gin.New()
 	r.Use(gin.Logger(), gin.Recovery(), middleware.CORSMiddleware())
 
+	if cfg.Storage.Driver == "local" {
+		r.Static(uploadsPath, cfg.Storage.LocalDir)
+	}
+
 	api := r.Group(cfg.API.Prefix + "/" + cfg.API.Version)
 
 	authGroup := api.Group("/auth")
@@ -65,13 +116,32 @@ func main() {
 		authGroup.GET("/me", middleware.AuthMiddleware(jwtManager), authHandler.GetMe)
 	}
 
+	api.GET("/amenities", spaceHandler.ListAmenities)
+
 	spacesGroup := api.Group("/spaces")
 	{
 		spacesGroup.GET("", spaceHandler.ListSpaces)
+		spacesGroup.GET("/:id/availability", bookingHandler.SpaceAvailability)
+		spacesGroup.GET("/:id/quote", spaceHandler.QuotePrice)
+		spacesGroup.GET("/:id/reviews", reviewHandler.ListSpaceReviews)
+		spacesGroup.GET("/:id/photos", spaceHandler.ListPhotos)
 	}
 	ownerSpaces := api.Group("/spaces", middleware.AuthMiddleware(jwtManager), middleware.OwnerOnlyMiddleware())
 	{
 		ownerSpaces.POST("", spaceHandler.CreateSpace)
+		ownerSpaces.GET("/my", spaceHandler.MySpaces)
+		ownerSpaces.PATCH("/:id", spaceHandler.UpdateSpace)
+		ownerSpaces.PATCH("/:id/archive", spaceHandler.ArchiveSpace)
+		ownerSpaces.PATCH("/:id/unarchive", spaceHandler.UnarchiveSpace)
+		ownerSpaces.DELETE("/:id", spaceHandler.DeleteSpace)
+		ownerSpaces.GET("/:id/pricing-rules", spaceHandler.ListPricingRules)
+		ownerSpaces.POST("/:id/pricing-rules", spaceHandler.CreatePricingRule)
+		ownerSpaces.DELETE("/:id/pricing-rules/:ruleId", spaceHandler.DeletePricingRule)
+		ownerSpaces.GET("/:id/price-history", spaceHandler.PriceHistory)
+		ownerSpaces.POST("/:id/photos", spaceHandler.UploadPhoto)
+		ownerSpaces.PATCH("/:id/photos/order", spaceHandler.ReorderPhotos)
+		ownerSpaces.PATCH("/:id/photos/:photoId/cover", spaceHandler.SetCoverPhoto)
+		ownerSpaces.DELETE("/:id/photos/:photoId", spaceHandler.DeletePhoto)
 	}
 
 	bookingsGroup := api.Group("/bookings", middleware.AuthMiddleware(jwtManager))
@@ -79,7 +149,17 @@ func main() {
 		bookingsGroup.POST("", middleware.RoleMiddleware(domain.RoleTenant), bookingHandler.CreateBooking)
 		bookingsGroup.GET("/my", middleware.RoleMiddleware(domain.RoleTenant), bookingHandler.MyBookings)
 		bookingsGroup.PATCH("/:id/cancel", middleware.RoleMiddleware(domain.RoleTenant), bookingHandler.CancelBooking)
+		bookingsGroup.GET("/:id/cancel/preview", middleware.RoleMiddleware(domain.RoleTenant), bookingHandler.PreviewCancellation)
+		bookingsGroup.POST("/:id/review", middleware.RoleMiddleware(domain.RoleTenant), reviewHandler.ReviewSpace)
 		bookingsGroup.GET("/:id/history", bookingHandler.GetBookingHistory)
+		bookingsGroup.GET("/:id/messages", messageHandler.BookingMessages)
+		bookingsGroup.POST("/:id/messages", messageHandler.SendBookingMessage)
+		bookingsGroup.POST("/:id/reschedule", middleware.RoleMiddleware(domain.RoleTenant), bookingHandler.RequestReschedule)
+		bookingsGroup.GET("/:id/reschedule", bookingHandler.ListReschedules)
+		bookingsGroup.PATCH("/:id/reschedule/withdraw", middleware.RoleMiddleware(domain.RoleTenant), bookingHandler.WithdrawReschedule)
+		bookingsGroup.POST("/recurring", middleware.RoleMiddleware(domain.RoleTenant), bookingHandler.CreateRecurringBooking)
+		bookingsGroup.GET("/series/:id", bookingHandler.GetSeries)
+		bookingsGroup.PATCH("/series/:id/cancel", middleware.RoleMiddleware(domain.RoleTenant), bookingHandler.CancelSeries)
 	}
 
 	ownerBookings := api.Group("/owner/bookings",
@@ -90,6 +170,62 @@ func main() {
 		ownerBookings.GET("", bookingHandler.OwnerBookings)
 		ownerBookings.PATCH("/:id/approve", bookingHandler.ApproveBooking)
 		ownerBookings.PATCH("/:id/reject", bookingHandler.RejectBooking)
+		ownerBookings.PATCH("/:id/cancel", bookingHandler.OwnerCancelBooking)
+		ownerBookings.PATCH("/:id/check-in", bookingHandler.CheckIn)
+		ownerBookings.PATCH("/:id/check-out", bookingHandler.CheckOut)
+		ownerBookings.PATCH("/:id/no-show", bookingHandler.MarkNoShow)
+		ownerBookings.POST("/:id/review", reviewHandler.ReviewTenant)
+		ownerBookings.PATCH("/:id/reschedule/approve", bookingHandler.ApproveReschedule)
+		ownerBookings.PATCH("/:id/reschedule/decline", bookingHandler.DeclineReschedule)
+		ownerBookings.PATCH("/series/:id/approve", bookingHandler.ApproveSeries)
+		ownerBookings.PATCH("/series/:id/reject", bookingHandler.RejectSeries)
+	}
+
+	reviewsGroup := api.Group("/reviews", middleware.AuthMiddleware(jwtManager), middleware.OwnerOnlyMiddleware())
+	{
+		reviewsGroup.POST("/:id/reply", reviewHandler.Reply)
+	}
+
+	conversationsGroup := api.Group("/conversations", middleware.AuthMiddleware(jwtManager))
+	{
+		conversationsGroup.GET("", messageHandler.ListConversations)
+		conversationsGroup.POST("/inquiries", middleware.RoleMiddleware(domain.RoleTenant), messageHandler.CreateInquiry)
+		conversationsGroup.GET("/:id/messages", messageHandler.ConversationMessages)
+		conversationsGroup.POST("/:id/messages", messageHandler.SendMessage)
+	}
+
+	favoritesGroup := api.Group("/favorites", middleware.AuthMiddleware(jwtManager))
+	{
+		favoritesGroup.GET("", favoritesHandler.ListFavorites)
+		favoritesGroup.POST("/:id", favoritesHandler.AddFavorite)
+		favoritesGroup.DELETE("/:id", favoritesHandler.RemoveFavorite)
+		favoritesGroup.GET("/price-alerts", favoritesHandler.PriceAlertSettings)
+		favoritesGroup.PATCH("/price-alerts", favoritesHandler.UpdatePriceAlertSettings)
+		favoritesGroup.GET("/collections", favoritesHandler.ListCollections)
+		favoritesGroup.POST("/collections", favoritesHandler.CreateCollection)
+		favoritesGroup.GET("/collections/:id", favoritesHandler.GetCollection)
+		favoritesGroup.PATCH("/collections/:id", favoritesHandler.RenameCollection)
+		favoritesGroup.DELETE("/collections/:id", favoritesHandler.DeleteCollection)
+		favoritesGroup.POST("/collections/:id/spaces", favoritesHandler.AddCollectionSpaces)
+		favoritesGroup.DELETE("/collections/:id/spaces/:spaceId", favoritesHandler.RemoveCollectionSpace)
+		favoritesGroup.POST("/collections/:id/share", favoritesHandler.ShareCollection)
+		favoritesGroup.DELETE("/collections/:id/share", favoritesHandler.UnshareCollection)
+	}
+	api.GET("/shared/collections/:token", favoritesHandler.SharedCollection)
+
+	savedSearchesGroup := api.Group("/saved-searches", middleware.AuthMiddleware(jwtManager))
+	{
+		savedSearchesGroup.GET("", savedSearchHandler.List)
+		savedSearchesGroup.POST("", savedSearchHandler.Create)
+		savedSearchesGroup.PATCH("/:id/pause", savedSearchHandler.Pause)
+		savedSearchesGroup.PATCH("/:id/resume", savedSearchHandler.Resume)
+		savedSearchesGroup.DELETE("/:id", savedSearchHandler.Delete)
+	}
+
+	usersGroup := api.Group("/users", middleware.AuthMiddleware(jwtManager))
+	{
+		usersGroup.GET("/:id", reviewHandler.GetProfile)
+		usersGroup.GET("/:id/reviews", reviewHandler.ListUserReviews)
 	}
 
 	srv := &http.Server{
@@ -101,8 +237,32 @@ func main() {
 		os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	bookingWorker := worker.NewBookingEventWorker(eventsChan)
-	go bookingWorker.Run(ctx)
+	outboxOptions := worker.OutboxOptions{
+		PollInterval: cfg.Outbox.PollInterval,
+		BatchSize:    cfg.Outbox.BatchSize,
+		MaxAttempts:  cfg.Outbox.MaxAttempts,
+		RetryBackoff: cfg.Outbox.RetryBackoff,
+		Retention:    cfg.Outbox.Retention,
+	}
+
+	bookingWorker := worker.NewBookingEventWorker()
+	outboxDispatcher := worker.NewOutboxDispatcher("booking_outbox", outboxRepo, outboxOptions)
+	outboxDispatcher.Subscribe(bookingWorker.Handle)
+	go outboxDispatcher.Run(ctx)
+
+	notificationWorker := worker.NewNotificationWorker()
+	notificationDispatcher := worker.NewOutboxDispatcher("notification_outbox", notificationOutboxRepo, outboxOptions)
+	notificationDispatcher.Subscribe(notificationWorker.Handle)
+	go notificationDispatcher.Run(ctx)
+
+	expiryJob := worker.NewBookingExpiryJob(bookingService, cfg.Booking.PendingTTL, cfg.Booking.ExpiryInterval)
+	go expiryJob.Run(ctx)
+
+	completionJob := worker.NewBookingCompletionJob(bookingService, cfg.Booking.CompletionInterval)
+	go completionJob.Run(ctx)
+
+	savedSearchJob := worker.NewSavedSearchJob(savedSearchService, cfg.Search.SavedSearchInterval)
+	go savedSearchJob.Run(ctx)
 
 	go func() {
 		log.Printf("server listening on :%s", cfg.Server.Port)
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -13,6 +14,10 @@ type Config struct {
 	Server   ServerConfig
 	JWT      JWTConfig
 	API      APIConfig
+	Booking  BookingConfig
+	Storage  StorageConfig
+	Search   SearchConfig
+	Outbox   OutboxConfig
 }
 
 type DatabaseConfig struct {
@@ -40,6 +45,55 @@ type APIConfig struct {
 	Prefix  string
 }
 
+type BookingConfig struct {
+	// Сколько заявка может ждать решения владельца
+	PendingTTL time.Duration
+	// Как часто запускать поиск истёкших заявок
+	ExpiryInterval time.Duration
+	// Как часто завершать брони, период которых закончился
+	CompletionInterval time.Duration
+	// Сколько времени после выезда можно оставить отзыв
+	ReviewWindow time.Duration
+}
+
+type StorageConfig struct {
+	// local или s3
+	Driver string
+	// Каталог для файлов и их публичный префикс при Driver=local
+	LocalDir  string
+	PublicURL string
+
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+
+	MaxPhotoBytes int64
+}
+
+type SearchConfig struct {
+	// haversine (обычный Postgres) или postgis
+	GeoBackend string
+	// Конфигурация Postgres для разбора поискового запроса q
+	TextConfig string
+	// Как часто проверять сохранённые поиски на новые пространства
+	SavedSearchInterval time.Duration
+}
+
+type OutboxConfig struct {
+	// Как часто диспетчер проверяет outbox на новые события
+	PollInterval time.Duration
+	// Сколько событий забирать за один запрос
+	BatchSize int
+	// После скольких неудачных попыток событие снимается с доставки
+	MaxAttempts int
+	// Задержка перед первой повторной попыткой, дальше удваивается
+	RetryBackoff time.Duration
+	// Сколько хранить доставленные события
+	Retention time.Duration
+}
+
 func LoadConfig() (*Config, error) {
 	if err := godotenv.Load(); err != nil {
 		fmt.Println("No .env file found, using environment variables")
@@ -67,6 +121,35 @@ func LoadConfig() (*Config, error) {
 			Version: getEnv("API_VERSION", "v1"),
 			Prefix:  getEnv("API_PREFIX", "/api"),
 		},
+		Booking: BookingConfig{
+			PendingTTL:         parseDuration(getEnv("BOOKING_PENDING_TTL", "48h"), 48*time.Hour),
+			ExpiryInterval:     parseDuration(getEnv("BOOKING_EXPIRY_INTERVAL", "10m"), 10*time.Minute),
+			CompletionInterval: parseDuration(getEnv("BOOKING_COMPLETION_INTERVAL", "10m"), 10*time.Minute),
+			ReviewWindow:       parseDuration(getEnv("BOOKING_REVIEW_WINDOW", "336h"), 14*24*time.Hour),
+		},
+		Storage: StorageConfig{
+			Driver:        getEnv("STORAGE_DRIVER", "local"),
+			LocalDir:      getEnv("STORAGE_LOCAL_DIR", "./uploads"),
+			PublicURL:     getEnv("STORAGE_PUBLIC_URL", ""),
+			S3Endpoint:    getEnv("S3_ENDPOINT", ""),
+			S3Region:      getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:      getEnv("S3_BUCKET", ""),
+			S3AccessKey:   getEnv("S3_ACCESS_KEY", ""),
+			S3SecretKey:   getEnv("S3_SECRET_KEY", ""),
+			MaxPhotoBytes: parseInt64(getEnv("PHOTO_MAX_BYTES", "10485760"), 10<<20),
+		},
+		Search: SearchConfig{
+			GeoBackend:          getEnv("GEO_BACKEND", "haversine"),
+			TextConfig:          getEnv("SEARCH_TEXT_CONFIG", "spacebook"),
+			SavedSearchInterval: parseDuration(getEnv("SAVED_SEARCH_INTERVAL", "15m"), 15*time.Minute),
+		},
+		Outbox: OutboxConfig{
+			PollInterval: parseDuration(getEnv("OUTBOX_POLL_INTERVAL", "1s"), time.Second),
+			BatchSize:    int(parseInt64(getEnv("OUTBOX_BATCH_SIZE", "100"), 100)),
+			MaxAttempts:  int(parseInt64(getEnv("OUTBOX_MAX_ATTEMPTS", "10"), 10)),
+			RetryBackoff: parseDuration(getEnv("OUTBOX_RETRY_BACKOFF", "5s"), 5*time.Second),
+			Retention:    parseDuration(getEnv("OUTBOX_RETENTION", "168h"), 7*24*time.Hour),
+		},
 	}
 
 	return config, nil
@@ -84,10 +167,20 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// parseDuration возвращает defaultDuration и для нуля или отрицательного значения:
+// все длительности здесь — интервалы тикеров, TTL и задержки, где ≤0 не имеет смысла
 func parseDuration(s string, defaultDuration time.Duration) time.Duration {
 	duration, err := time.ParseDuration(s)
-	if err != nil {
+	if err != nil || duration <= 0 {
 		return defaultDuration
 	}
 	return duration
 }
+
+func parseInt64(s string, defaultValue int64) int64 {
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || v <= 0 {
+		return defaultValue
+	}
+	return v
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	const def = 10 * time.Minute
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"5s", 5 * time.Second},
+		{"1h30m", 90 * time.Minute},
+		{"", def},
+		{"abc", def},
+		{"0", def},
+		{"0s", def},
+		{"-1m", def},
+	}
+	for _, tt := range tests {
+		if got := parseDuration(tt.in, def); got != tt.want {
+			t.Errorf("parseDuration(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+// Нулевая задержка повтора превратила бы backoff в повтор без паузы
+func TestLoadConfigOutboxRetryBackoff(t *testing.T) {
+	for _, v := range []string{"0", "0s", "-5s"} {
+		t.Setenv("OUTBOX_RETRY_BACKOFF", v)
+		cfg, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("load config: %v", err)
+		}
+		if cfg.Outbox.RetryBackoff != 5*time.Second {
+			t.Errorf("OUTBOX_RETRY_BACKOFF=%s: got %s, want 5s", v, cfg.Outbox.RetryBackoff)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+package domain
+
+// Удобство из справочника; Code — стабильный идентификатор для фильтров и запросов
+type Amenity struct {
+	ID   int    `json:"id" db:"id"`
+	Code string `json:"code" db:"code"`
+	Name string `json:"name" db:"name"`
+}
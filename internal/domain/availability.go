@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+type AvailabilityStatus string
+
+const (
+	AvailabilityFree    AvailabilityStatus = "free"
+	AvailabilityPending AvailabilityStatus = "pending"
+	AvailabilityBooked  AvailabilityStatus = "booked"
+)
+
+// Непрерывный отрезок календаря [From, To) с одним статусом и ценой за слот
+type AvailabilityRange struct {
+	From   time.Time          `json:"from"`
+	To     time.Time          `json:"to"`
+	Status AvailabilityStatus `json:"status"`
+	Price  int                `json:"price"`
+}
+
+// Unit показывает шаг календаря: сутки или час
+type SpaceAvailability struct {
+	SpaceID int                 `json:"space_id"`
+	Unit    BookingUnit         `json:"unit"`
+	From    time.Time           `json:"from"`
+	To      time.Time           `json:"to"`
+	Ranges  []AvailabilityRange `json:"ranges"`
+}
@@ -9,35 +9,73 @@ const (
 	BookingStatusApproved  BookingStatus = "approved"
 	BookingStatusRejected  BookingStatus = "rejected"
 	BookingStatusCancelled BookingStatus = "cancelled"
+	BookingStatusExpired   BookingStatus = "expired"
+	BookingStatusCompleted BookingStatus = "completed"
+	BookingStatusNoShow    BookingStatus = "no_show"
 )
 
+// Кто сменил статус бронирования
+type HistoryActor string
+
+const (
+	HistoryActorTenant HistoryActor = "tenant"
+	HistoryActorOwner  HistoryActor = "owner"
+	HistoryActorSystem HistoryActor = "system"
+)
+
+// TotalPrice, Currency и политика отмены фиксируются при создании брони; у старых броней их нет.
+// RefundPct и RefundAmount заполняются при отмене, CheckedInAt и CheckedOutAt — владельцем при заезде и выезде.
 type Booking struct {
-	ID        int           `json:"id" db:"id"`
-	SpaceID   int           `json:"space_id" db:"space_id"`
-	TenantID  int           `json:"tenant_id" db:"tenant_id"`
-	Status    BookingStatus `json:"status" db:"status"`
-	DateFrom  time.Time     `json:"date_from" db:"date_from"`
-	DateTo    time.Time     `json:"date_to" db:"date_to"`
-	CreatedAt time.Time     `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time     `json:"updated_at" db:"updated_at"`
+	ID                 int                 `json:"id" db:"id"`
+	SpaceID            int                 `json:"space_id" db:"space_id"`
+	TenantID           int                 `json:"tenant_id" db:"tenant_id"`
+	Status             BookingStatus       `json:"status" db:"status"`
+	DateFrom           time.Time           `json:"date_from" db:"date_from"`
+	DateTo             time.Time           `json:"date_to" db:"date_to"`
+	TotalPrice         *int                `json:"total_price,omitempty" db:"total_price"`
+	Currency           *string             `json:"currency,omitempty" db:"currency"`
+	SeriesID           *int                `json:"series_id,omitempty" db:"series_id"`
+	CancellationPolicy *CancellationPolicy `json:"cancellation_policy,omitempty" db:"cancellation_policy"`
+	CancellationTiers  []CancellationTier  `json:"-" db:"cancellation_tiers"`
+	RefundPct          *int                `json:"refund_pct,omitempty" db:"refund_pct"`
+	RefundAmount       *int                `json:"refund_amount,omitempty" db:"refund_amount"`
+	CheckedInAt        *time.Time          `json:"checked_in_at,omitempty" db:"checked_in_at"`
+	CheckedOutAt       *time.Time          `json:"checked_out_at,omitempty" db:"checked_out_at"`
+	CreatedAt          time.Time           `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time           `json:"updated_at" db:"updated_at"`
 }
 
+// DateFrom и DateTo — даты YYYY-MM-DD для посуточных пространств
+// или моменты времени RFC3339 для почасовых
 type CreateBookingRequest struct {
 	SpaceID  int    `json:"space_id" binding:"required"`
 	DateFrom string `json:"date_from" binding:"required"`
 	DateTo   string `json:"date_to" binding:"required"`
 }
 
-// История изменения статуса бронирования
+// История изменения статуса бронирования.
+// Для переносов дат заполнены старый и новый периоды, для отмен — назначенный возврат.
 type BookingStatusHistory struct {
-	ID        int            `json:"id" db:"id"`
-	BookingID int            `json:"booking_id" db:"booking_id"`
-	OldStatus *BookingStatus `json:"old_status,omitempty" db:"old_status"`
-	NewStatus BookingStatus  `json:"new_status" db:"new_status"`
-	ChangedBy int            `json:"changed_by" db:"changed_by"`
-	ChangedAt time.Time      `json:"changed_at" db:"changed_at"`
-	Reason    *string        `json:"reason,omitempty" db:"reason"`
-	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+	ID           int            `json:"id" db:"id"`
+	BookingID    int            `json:"booking_id" db:"booking_id"`
+	OldStatus    *BookingStatus `json:"old_status,omitempty" db:"old_status"`
+	NewStatus    BookingStatus  `json:"new_status" db:"new_status"`
+	ChangedBy    *int           `json:"changed_by,omitempty" db:"changed_by"`
+	Actor        HistoryActor   `json:"actor" db:"actor"`
+	ChangedAt    time.Time      `json:"changed_at" db:"changed_at"`
+	Reason       *string        `json:"reason,omitempty" db:"reason"`
+	OldDateFrom  *time.Time     `json:"old_date_from,omitempty" db:"old_date_from"`
+	OldDateTo    *time.Time     `json:"old_date_to,omitempty" db:"old_date_to"`
+	NewDateFrom  *time.Time     `json:"new_date_from,omitempty" db:"new_date_from"`
+	NewDateTo    *time.Time     `json:"new_date_to,omitempty" db:"new_date_to"`
+	RefundPct    *int           `json:"refund_pct,omitempty" db:"refund_pct"`
+	RefundAmount *int           `json:"refund_amount,omitempty" db:"refund_amount"`
+	CreatedAt    time.Time      `json:"created_at" db:"created_at"`
+}
+
+// Владелец отменяет одобренную бронь только с указанием причины
+type OwnerCancelBookingRequest struct {
+	Reason string `json:"reason" binding:"required"`
 }
 
 // Запрос для изменения статуса с причиной (опционально)
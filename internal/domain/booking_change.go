@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+type BookingChangeStatus string
+
+const (
+	BookingChangePending   BookingChangeStatus = "pending"
+	BookingChangeApproved  BookingChangeStatus = "approved"
+	BookingChangeDeclined  BookingChangeStatus = "declined"
+	BookingChangeWithdrawn BookingChangeStatus = "withdrawn"
+)
+
+// Заявка арендатора на перенос брони на другие даты.
+// TotalPrice — стоимость брони в новых датах, посчитанная при подаче заявки.
+type BookingChangeRequest struct {
+	ID          int                 `json:"id" db:"id"`
+	BookingID   int                 `json:"booking_id" db:"booking_id"`
+	RequestedBy int                 `json:"requested_by" db:"requested_by"`
+	OldDateFrom time.Time           `json:"old_date_from" db:"old_date_from"`
+	OldDateTo   time.Time           `json:"old_date_to" db:"old_date_to"`
+	NewDateFrom time.Time           `json:"new_date_from" db:"new_date_from"`
+	NewDateTo   time.Time           `json:"new_date_to" db:"new_date_to"`
+	TotalPrice  *int                `json:"total_price,omitempty" db:"total_price"`
+	Currency    *string             `json:"currency,omitempty" db:"currency"`
+	Status      BookingChangeStatus `json:"status" db:"status"`
+	Reason      *string             `json:"reason,omitempty" db:"reason"`
+	DecidedBy   *int                `json:"decided_by,omitempty" db:"decided_by"`
+	DecidedAt   *time.Time          `json:"decided_at,omitempty" db:"decided_at"`
+	CreatedAt   time.Time           `json:"created_at" db:"created_at"`
+}
+
+type RescheduleBookingRequest struct {
+	DateFrom string  `json:"date_from" binding:"required"`
+	DateTo   string  `json:"date_to" binding:"required"`
+	Reason   *string `json:"reason,omitempty"`
+}
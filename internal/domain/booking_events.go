@@ -9,12 +9,36 @@ const (
 	BookingEventApproved  BookingEventType = "approved"
 	BookingEventRejected  BookingEventType = "rejected"
 	BookingEventCancelled BookingEventType = "cancelled"
+	BookingEventExpired   BookingEventType = "expired"
+	BookingEventCheckedIn BookingEventType = "checked_in"
+	BookingEventCompleted BookingEventType = "completed"
+	BookingEventNoShow    BookingEventType = "no_show"
+
+	BookingEventCancelledByOwner BookingEventType = "cancelled_by_owner"
+
+	BookingEventRescheduleRequested BookingEventType = "reschedule_requested"
+	BookingEventRescheduleApproved  BookingEventType = "reschedule_approved"
+	BookingEventRescheduleDeclined  BookingEventType = "reschedule_declined"
+
+	BookingEventMessageSent BookingEventType = "message_sent"
 )
 
+// Для сообщений заполнены ConversationID и SenderID (получатель — другая сторона переписки);
+// у вопросов до бронирования BookingID равен 0
 type BookingEvent struct {
-	Type      BookingEventType `json:"type"`
-	BookingID int              `json:"booking_id"`
-	SpaceID   int              `json:"space_id"`
-	TenantID  int              `json:"tenant_id"`
-	At        time.Time        `json:"at"`
+	Type           BookingEventType `json:"type"`
+	BookingID      int              `json:"booking_id"`
+	SpaceID        int              `json:"space_id"`
+	TenantID       int              `json:"tenant_id"`
+	ConversationID int              `json:"conversation_id,omitempty"`
+	SenderID       int              `json:"sender_id,omitempty"`
+	At             time.Time        `json:"at"`
+}
+
+// OutboxEvent — событие E (BookingEvent или NotificationEvent) из outbox-таблицы;
+// Attempts — число попыток доставки, включая текущую
+type OutboxEvent[E any] struct {
+	ID       int64
+	Event    E
+	Attempts int
 }
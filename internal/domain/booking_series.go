@@ -0,0 +1,54 @@
+package domain
+
+import "time"
+
+type RecurrenceFreq string
+
+const (
+	RecurrenceWeekly  RecurrenceFreq = "weekly"
+	RecurrenceMonthly RecurrenceFreq = "monthly"
+)
+
+// Серия повторяющихся броней (аналог RRULE: FREQ, INTERVAL, UNTIL, COUNT)
+type BookingSeries struct {
+	ID        int            `json:"id" db:"id"`
+	SpaceID   int            `json:"space_id" db:"space_id"`
+	TenantID  int            `json:"tenant_id" db:"tenant_id"`
+	Freq      RecurrenceFreq `json:"freq" db:"freq"`
+	Interval  int            `json:"interval" db:"interval"`
+	Until     *time.Time     `json:"until,omitempty" db:"until"`
+	Count     *int           `json:"count,omitempty" db:"count"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+}
+
+// Until — последняя дата начала повторения включительно (YYYY-MM-DD)
+type RecurrenceRule struct {
+	Freq     RecurrenceFreq `json:"freq" binding:"required,oneof=weekly monthly"`
+	Interval int            `json:"interval" binding:"omitempty,gte=1"`
+	Until    *string        `json:"until"`
+	Count    *int           `json:"count" binding:"omitempty,gte=1"`
+}
+
+// DateFrom и DateTo задают первое повторение; остальные сдвигаются по правилу.
+// Без SkipConflicts серия не создаётся, если хотя бы одно повторение занято.
+type CreateRecurringBookingRequest struct {
+	SpaceID       int            `json:"space_id" binding:"required"`
+	DateFrom      string         `json:"date_from" binding:"required"`
+	DateTo        string         `json:"date_to" binding:"required"`
+	Recurrence    RecurrenceRule `json:"recurrence" binding:"required"`
+	SkipConflicts bool           `json:"skip_conflicts"`
+}
+
+// Повторение, которое не удалось создать или обработать
+type OccurrenceConflict struct {
+	BookingID *int      `json:"booking_id,omitempty"`
+	DateFrom  time.Time `json:"date_from"`
+	DateTo    time.Time `json:"date_to"`
+	Reason    string    `json:"reason"`
+}
+
+type BookingSeriesDetails struct {
+	Series    BookingSeries        `json:"series"`
+	Bookings  []Booking            `json:"bookings"`
+	Conflicts []OccurrenceConflict `json:"conflicts,omitempty"`
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+type CancellationPolicy string
+
+const (
+	CancellationFlexible CancellationPolicy = "flexible"
+	CancellationModerate CancellationPolicy = "moderate"
+	CancellationStrict   CancellationPolicy = "strict"
+	CancellationCustom   CancellationPolicy = "custom"
+)
+
+// Ступень политики отмены: при отмене не позже чем за DaysBefore дней
+// до начала брони возвращается RefundPct процентов стоимости
+type CancellationTier struct {
+	DaysBefore int `json:"days_before" binding:"gte=0"`
+	RefundPct  int `json:"refund_pct" binding:"gte=0,lte=100"`
+}
+
+// Расчёт возврата при отмене брони в момент CalculatedAt.
+// RefundAmount пуст, если у брони не зафиксирована стоимость.
+type RefundQuote struct {
+	BookingID    int                `json:"booking_id"`
+	Policy       CancellationPolicy `json:"policy"`
+	Tiers        []CancellationTier `json:"tiers"`
+	RefundPct    int                `json:"refund_pct"`
+	RefundAmount *int               `json:"refund_amount,omitempty"`
+	TotalPrice   *int               `json:"total_price,omitempty"`
+	Currency     *string            `json:"currency,omitempty"`
+	CalculatedAt time.Time          `json:"calculated_at"`
+}
@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// Collection — именованная подборка пространств пользователя.
+// ShareToken задан, пока подборка открыта по ссылке.
+type Collection struct {
+	ID         int       `json:"id" db:"id"`
+	UserID     int       `json:"user_id" db:"user_id"`
+	Name       string    `json:"name" db:"name"`
+	ShareToken *string   `json:"share_token,omitempty" db:"share_token"`
+	SpaceCount int       `json:"space_count"`
+	Spaces     []Space   `json:"spaces,omitempty"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// SharedCollection — подборка, открытая по ссылке без авторизации:
+// без владельца и самого токена, только название и пространства
+type SharedCollection struct {
+	Name       string    `json:"name"`
+	SpaceCount int       `json:"space_count"`
+	Spaces     []Space   `json:"spaces"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Используется и при создании, и при переименовании
+type CollectionNameRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=100"`
+}
+
+type AddCollectionSpacesRequest struct {
+	SpaceIDs []int `json:"space_ids" binding:"required,min=1,dive,gt=0"`
+}
@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// Переписка по брони или, если BookingID пуст, вопрос по пространству до бронирования.
+// UnreadCount считается для пользователя, который запрашивает список.
+type Conversation struct {
+	ID            int        `json:"id" db:"id"`
+	SpaceID       int        `json:"space_id" db:"space_id"`
+	BookingID     *int       `json:"booking_id,omitempty" db:"booking_id"`
+	TenantID      int        `json:"tenant_id" db:"tenant_id"`
+	OwnerID       int        `json:"owner_id" db:"owner_id"`
+	LastMessageAt *time.Time `json:"last_message_at,omitempty" db:"last_message_at"`
+	UnreadCount   int        `json:"unread_count"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+}
+
+// ReadAt — когда сообщение прочитал получатель
+type Message struct {
+	ID             int        `json:"id" db:"id"`
+	ConversationID int        `json:"conversation_id" db:"conversation_id"`
+	SenderID       int        `json:"sender_id" db:"sender_id"`
+	Body           string     `json:"body" db:"body"`
+	ReadAt         *time.Time `json:"read_at,omitempty" db:"read_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+}
+
+type SendMessageRequest struct {
+	Body string `json:"body" binding:"required,max=4000"`
+}
+
+type CreateInquiryRequest struct {
+	SpaceID int    `json:"space_id" binding:"required"`
+	Body    string `json:"body" binding:"required,max=4000"`
+}
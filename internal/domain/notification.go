@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+type NotificationType string
+
+const (
+	NotificationSavedSearchMatch NotificationType = "saved_search_match"
+	NotificationPriceDrop        NotificationType = "price_drop"
+)
+
+// NotificationEvent — уведомление пользователю, не связанное с конкретной бронью.
+// Для снижения цены заполнены OldPrice и NewPrice.
+type NotificationEvent struct {
+	Type          NotificationType `json:"type"`
+	UserID        int              `json:"user_id"`
+	SpaceID       int              `json:"space_id"`
+	SavedSearchID int              `json:"saved_search_id,omitempty"`
+	OldPrice      int              `json:"old_price,omitempty"`
+	NewPrice      int              `json:"new_price,omitempty"`
+	At            time.Time        `json:"at"`
+}
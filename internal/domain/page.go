@@ -0,0 +1,9 @@
+package domain
+
+// Page — одна страница списка. NextCursor равен nil на последней странице,
+// Total — число элементов по тем же фильтрам без учёта пагинации.
+type Page[T any] struct {
+	Items      []T     `json:"items"`
+	NextCursor *string `json:"next_cursor"`
+	Total      int     `json:"total"`
+}
@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// Порог по умолчанию, пока пользователь не выбрал свой
+const DefaultPriceDropThresholdPct = 10
+
+// Точка истории базовой цены пространства
+type PricePoint struct {
+	Price     int       `json:"price" db:"price"`
+	Currency  string    `json:"currency" db:"currency"`
+	ChangedAt time.Time `json:"changed_at" db:"changed_at"`
+}
+
+// PriceAlertSettings — когда сообщать о снижении цены пространств из избранного:
+// цена должна упасть больше чем на ThresholdPct процентов
+type PriceAlertSettings struct {
+	Enabled      bool `json:"enabled"`
+	ThresholdPct int  `json:"threshold_pct"`
+}
+
+type UpdatePriceAlertSettingsRequest struct {
+	Enabled      *bool `json:"enabled"`
+	ThresholdPct *int  `json:"threshold_pct" binding:"omitempty,gte=0,lte=99"`
+}
+
+// Пользователь, которому пора сообщить о снижении цены
+type PriceDrop struct {
+	UserID   int
+	OldPrice int
+}
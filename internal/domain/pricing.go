@@ -0,0 +1,54 @@
+package domain
+
+import "time"
+
+// Расчёт стоимости бронирования пространства за период [DateFrom, DateTo).
+// Units — количество ночей или часов в зависимости от Unit.
+type PriceQuote struct {
+	SpaceID     int         `json:"space_id"`
+	DateFrom    time.Time   `json:"date_from"`
+	DateTo      time.Time   `json:"date_to"`
+	Unit        BookingUnit `json:"unit"`
+	Units       int         `json:"units"`
+	UnitPrice   int         `json:"unit_price"`
+	Breakdown   []SlotPrice `json:"breakdown"`
+	Subtotal    int         `json:"subtotal"`
+	DiscountPct int         `json:"discount_pct"`
+	Discount    int         `json:"discount"`
+	CleaningFee int         `json:"cleaning_fee"`
+	Total       int         `json:"total"`
+	Currency    string      `json:"currency"`
+}
+
+// Правило цены пространства. Для каждого слота применяется одно правило —
+// подходящее с наибольшим Priority, при равенстве — созданное позже.
+type PricingRule struct {
+	ID         int        `json:"id" db:"id"`
+	SpaceID    int        `json:"space_id" db:"space_id"`
+	Name       string     `json:"name" db:"name"`
+	DateFrom   *time.Time `json:"date_from,omitempty" db:"date_from"`
+	DateTo     *time.Time `json:"date_to,omitempty" db:"date_to"`
+	DaysOfWeek []int      `json:"days_of_week,omitempty" db:"days_of_week"`
+	Price      *int       `json:"price,omitempty" db:"price"`
+	AdjustPct  *int       `json:"adjust_pct,omitempty" db:"adjust_pct"`
+	Priority   int        `json:"priority" db:"priority"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+type CreatePricingRuleRequest struct {
+	Name       string  `json:"name" binding:"required,max=100"`
+	DateFrom   *string `json:"date_from"`
+	DateTo     *string `json:"date_to"`
+	DaysOfWeek []int   `json:"days_of_week" binding:"omitempty,dive,gte=0,lte=6"`
+	Price      *int    `json:"price" binding:"omitempty,gt=0"`
+	AdjustPct  *int    `json:"adjust_pct" binding:"omitempty,gte=-100"`
+	Priority   int     `json:"priority"`
+}
+
+// Цена одного слота (ночи или часа), начинающегося в Start;
+// RuleID пуст, если действует базовая цена пространства
+type SlotPrice struct {
+	Start  time.Time `json:"start"`
+	Price  int       `json:"price"`
+	RuleID *int      `json:"rule_id,omitempty"`
+}
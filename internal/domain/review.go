@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+// Кого оценивает отзыв: пространство (пишет арендатор) или арендатора (пишет владелец)
+type ReviewDirection string
+
+const (
+	ReviewOfSpace  ReviewDirection = "space"
+	ReviewOfTenant ReviewDirection = "tenant"
+)
+
+// Reply — публичный ответ владельца на отзыв о пространстве, не больше одного
+type Review struct {
+	ID        int             `json:"id" db:"id"`
+	BookingID int             `json:"booking_id" db:"booking_id"`
+	Direction ReviewDirection `json:"direction" db:"direction"`
+	AuthorID  int             `json:"author_id" db:"author_id"`
+	SpaceID   int             `json:"space_id" db:"space_id"`
+	TenantID  int             `json:"tenant_id" db:"tenant_id"`
+	Rating    int             `json:"rating" db:"rating"`
+	Comment   string          `json:"comment" db:"comment"`
+	Reply     *string         `json:"reply,omitempty" db:"reply"`
+	RepliedAt *time.Time      `json:"replied_at,omitempty" db:"replied_at"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+}
+
+type CreateReviewRequest struct {
+	Rating  int    `json:"rating" binding:"required,min=1,max=5"`
+	Comment string `json:"comment" binding:"max=2000"`
+}
+
+type ReviewReplyRequest struct {
+	Reply string `json:"reply" binding:"required,max=2000"`
+}
+
+// Публичный профиль пользователя без контактных данных.
+// Для арендатора рейтинг складывается из оценок владельцев.
+type UserProfile struct {
+	ID          int       `json:"id"`
+	FirstName   string    `json:"first_name"`
+	Role        UserRole  `json:"role"`
+	RatingAvg   float64   `json:"rating_avg"`
+	RatingCount int       `json:"rating_count"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// SavedSearch — именованный фильтр каталога. Пока поиск не на паузе,
+// фоновая задача уведомляет о новых подходящих пространствах.
+type SavedSearch struct {
+	ID        int               `json:"id" db:"id"`
+	UserID    int               `json:"user_id" db:"user_id"`
+	Name      string            `json:"name" db:"name"`
+	Filter    SpaceSearchFilter `json:"filter" db:"filter"`
+	IsPaused  bool              `json:"is_paused" db:"is_paused"`
+	CheckedAt time.Time         `json:"checked_at" db:"checked_at"`
+	CreatedAt time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// SpaceSearchFilter повторяет параметры GET /spaces; даты — YYYY-MM-DD или RFC3339
+type SpaceSearchFilter struct {
+	Query         string       `json:"q,omitempty"`
+	MinPrice      *int         `json:"min_price,omitempty" binding:"omitempty,gte=0"`
+	MaxPrice      *int         `json:"max_price,omitempty" binding:"omitempty,gte=0"`
+	MinArea       *float64     `json:"min_area,omitempty" binding:"omitempty,gte=0"`
+	MaxArea       *float64     `json:"max_area,omitempty" binding:"omitempty,gte=0"`
+	Amenities     []string     `json:"amenities,omitempty"`
+	Near          *SearchPoint `json:"near,omitempty"`
+	RadiusKm      *float64     `json:"radius_km,omitempty"`
+	AvailableFrom *string      `json:"available_from,omitempty"`
+	AvailableTo   *string      `json:"available_to,omitempty"`
+}
+
+type SearchPoint struct {
+	Lat float64 `json:"lat" binding:"gte=-90,lte=90"`
+	Lng float64 `json:"lng" binding:"gte=-180,lte=180"`
+}
+
+type CreateSavedSearchRequest struct {
+	Name   string            `json:"name" binding:"required,min=1,max=100"`
+	Filter SpaceSearchFilter `json:"filter"`
+}
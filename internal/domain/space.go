@@ -2,22 +2,104 @@ package domain
 
 import "time"
 
+// Валюта, в которой пространства выставляются по умолчанию
+const DefaultCurrency = "KZT"
+
+// Единица бронирования: посуточно или почасово
+type BookingUnit string
+
+const (
+	BookingUnitDay  BookingUnit = "day"
+	BookingUnitHour BookingUnit = "hour"
+)
+
+// Минимальная длительность почасовой брони по умолчанию
+const DefaultMinSlotMinutes = 60
+
+// Структурированный адрес пространства; Country — код ISO 3166-1 alpha-2
+type SpaceAddress struct {
+	Line       string `json:"line" db:"address_line"`
+	City       string `json:"city" db:"city"`
+	Region     string `json:"region" db:"region"`
+	PostalCode string `json:"postal_code" db:"postal_code"`
+	Country    string `json:"country" db:"country" binding:"omitempty,len=2,uppercase"`
+}
+
+// Price — цена за единицу бронирования (ночь или час).
+// DistanceKm заполняется только при поиске рядом с точкой,
+// Rank и Snippet — только при полнотекстовом поиске.
 type Space struct {
-	ID          int       `json:"id" db:"id"`
-	OwnerID     int       `json:"owner_id" db:"owner_id"`
-	Title       string    `json:"title" db:"title"`
-	Description string    `json:"description" db:"description"`
-	AreaM2      float64   `json:"area_m2" db:"area_m2"`
-	Price       int       `json:"price" db:"price"`
-	Phone       string    `json:"phone" db:"phone"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	ID                 int                `json:"id" db:"id"`
+	OwnerID            int                `json:"owner_id" db:"owner_id"`
+	Title              string             `json:"title" db:"title"`
+	Description        string             `json:"description" db:"description"`
+	AreaM2             float64            `json:"area_m2" db:"area_m2"`
+	Price              int                `json:"price" db:"price"`
+	Currency           string             `json:"currency" db:"currency"`
+	CleaningFee        int                `json:"cleaning_fee" db:"cleaning_fee"`
+	WeeklyDiscountPct  int                `json:"weekly_discount_pct" db:"weekly_discount_pct"`
+	MonthlyDiscountPct int                `json:"monthly_discount_pct" db:"monthly_discount_pct"`
+	BookingUnit        BookingUnit        `json:"booking_unit" db:"booking_unit"`
+	MinSlotMinutes     int                `json:"min_slot_minutes" db:"min_slot_minutes"`
+	CancellationPolicy CancellationPolicy `json:"cancellation_policy" db:"cancellation_policy"`
+	CancellationTiers  []CancellationTier `json:"cancellation_tiers,omitempty" db:"cancellation_tiers"`
+	Phone              string             `json:"phone" db:"phone"`
+	Address            SpaceAddress       `json:"address"`
+	Latitude           *float64           `json:"latitude,omitempty" db:"latitude"`
+	Longitude          *float64           `json:"longitude,omitempty" db:"longitude"`
+	DistanceKm         *float64           `json:"distance_km,omitempty"`
+	Rank               *float64           `json:"rank,omitempty"`
+	Snippet            *string            `json:"snippet,omitempty"`
+	OwnerCancellations int                `json:"owner_cancellations" db:"owner_cancellations"`
+	RatingAvg          float64            `json:"rating_avg" db:"rating_avg"`
+	RatingCount        int                `json:"rating_count" db:"rating_count"`
+	IsActive           bool               `json:"is_active" db:"is_active"`
+	CoverURL           string             `json:"cover_url,omitempty"`
+	Photos             []SpacePhoto       `json:"photos"`
+	Amenities          []Amenity          `json:"amenities"`
+	CreatedAt          time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time          `json:"updated_at" db:"updated_at"`
 }
 
 type CreateSpaceRequest struct {
-	Title       string  `json:"title" binding:"required"`
-	Description string  `json:"description" binding:"required"`
-	AreaM2      float64 `json:"area_m2" binding:"required,gt=0"`
-	Price       int     `json:"price" binding:"required,gt=0"`
-	Phone       string  `json:"phone" binding:"required"`
+	Title              string             `json:"title" binding:"required"`
+	Description        string             `json:"description" binding:"required"`
+	AreaM2             float64            `json:"area_m2" binding:"required,gt=0"`
+	Price              int                `json:"price" binding:"required,gt=0"`
+	Currency           string             `json:"currency" binding:"omitempty,len=3,uppercase"`
+	CleaningFee        int                `json:"cleaning_fee" binding:"gte=0"`
+	WeeklyDiscountPct  int                `json:"weekly_discount_pct" binding:"gte=0,lte=100"`
+	MonthlyDiscountPct int                `json:"monthly_discount_pct" binding:"gte=0,lte=100"`
+	BookingUnit        string             `json:"booking_unit" binding:"omitempty,oneof=day hour"`
+	MinSlotMinutes     int                `json:"min_slot_minutes" binding:"omitempty,gte=15"`
+	CancellationPolicy string             `json:"cancellation_policy" binding:"omitempty,oneof=flexible moderate strict custom"`
+	CancellationTiers  []CancellationTier `json:"cancellation_tiers" binding:"omitempty,dive"`
+	Amenities          []string           `json:"amenities"`
+	Address            SpaceAddress       `json:"address"`
+	Latitude           *float64           `json:"latitude" binding:"omitempty,gte=-90,lte=90"`
+	Longitude          *float64           `json:"longitude" binding:"omitempty,gte=-180,lte=180"`
+	Phone              string             `json:"phone" binding:"required"`
+}
+
+// Частичное обновление: меняются только переданные поля.
+// Amenities заменяет весь набор удобств; пустой массив убирает все.
+// Address заменяется целиком, координаты передаются парой.
+type UpdateSpaceRequest struct {
+	Title              *string            `json:"title" binding:"omitempty,min=1"`
+	Description        *string            `json:"description"`
+	AreaM2             *float64           `json:"area_m2" binding:"omitempty,gt=0"`
+	Price              *int               `json:"price" binding:"omitempty,gt=0"`
+	Currency           *string            `json:"currency" binding:"omitempty,len=3,uppercase"`
+	CleaningFee        *int               `json:"cleaning_fee" binding:"omitempty,gte=0"`
+	WeeklyDiscountPct  *int               `json:"weekly_discount_pct" binding:"omitempty,gte=0,lte=100"`
+	MonthlyDiscountPct *int               `json:"monthly_discount_pct" binding:"omitempty,gte=0,lte=100"`
+	BookingUnit        *string            `json:"booking_unit" binding:"omitempty,oneof=day hour"`
+	MinSlotMinutes     *int               `json:"min_slot_minutes" binding:"omitempty,gte=15"`
+	CancellationPolicy *string            `json:"cancellation_policy" binding:"omitempty,oneof=flexible moderate strict custom"`
+	CancellationTiers  []CancellationTier `json:"cancellation_tiers" binding:"omitempty,dive"`
+	Amenities          []string           `json:"amenities"`
+	Address            *SpaceAddress      `json:"address"`
+	Latitude           *float64           `json:"latitude" binding:"omitempty,gte=-90,lte=90"`
+	Longitude          *float64           `json:"longitude" binding:"omitempty,gte=-180,lte=180"`
+	Phone              *string            `json:"phone"`
 }
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// Фото пространства. Ключи в хранилище наружу не отдаются, вместо них — готовые URL.
+type SpacePhoto struct {
+	ID           int       `json:"id" db:"id"`
+	SpaceID      int       `json:"space_id" db:"space_id"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	StorageKey   string    `json:"-" db:"storage_key"`
+	ThumbKey     string    `json:"-" db:"thumb_key"`
+	ContentType  string    `json:"content_type" db:"content_type"`
+	SizeBytes    int64     `json:"size_bytes" db:"size_bytes"`
+	Width        int       `json:"width" db:"width"`
+	Height       int       `json:"height" db:"height"`
+	Position     int       `json:"position" db:"position"`
+	IsCover      bool      `json:"is_cover" db:"is_cover"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// Новый порядок фото: должны быть перечислены все фото пространства
+type ReorderPhotosRequest struct {
+	PhotoIDs []int `json:"photo_ids" binding:"required,min=1"`
+}
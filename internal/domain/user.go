@@ -19,6 +19,8 @@ type User struct {
 	FirstName    string    `json:"first_name" db:"first_name"`
 	LastName     string    `json:"last_name" db:"last_name"`
 	Phone        string    `json:"phone" db:"phone"`
+	RatingAvg    float64   `json:"rating_avg" db:"rating_avg"`
+	RatingCount  int       `json:"rating_count" db:"rating_count"`
 	CreatedAt    time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
 }
@@ -0,0 +1,71 @@
+package geo
+
+import "math"
+
+const (
+	EarthRadiusKm = 6371.0
+	// Длина одного градуса широты
+	kmPerDegree = 111.045
+)
+
+type Point struct {
+	Lat float64
+	Lng float64
+}
+
+// Box — прямоугольник на карте. Если MinLng > MaxLng, прямоугольник пересекает 180-й меридиан.
+type Box struct {
+	MinLat float64
+	MinLng float64
+	MaxLat float64
+	MaxLng float64
+}
+
+func (p Point) Valid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lng >= -180 && p.Lng <= 180
+}
+
+func (b Box) Valid() bool {
+	return b.MinLat >= -90 && b.MaxLat <= 90 && b.MinLat <= b.MaxLat &&
+		b.MinLng >= -180 && b.MinLng <= 180 && b.MaxLng >= -180 && b.MaxLng <= 180
+}
+
+// CrossesAntimeridian сообщает, что долготы прямоугольника надо проверять через OR
+func (b Box) CrossesAntimeridian() bool {
+	return b.MinLng > b.MaxLng
+}
+
+// BoundingBox возвращает прямоугольник, гарантированно содержащий круг радиуса radiusKm.
+// Используется как грубый предфильтр по индексу перед точным расчётом расстояния.
+// Возле полюсов долгота не ограничивается.
+func BoundingBox(center Point, radiusKm float64) Box {
+	dLat := radiusKm / kmPerDegree
+	box := Box{
+		MinLat: math.Max(center.Lat-dLat, -90),
+		MaxLat: math.Min(center.Lat+dLat, 90),
+		MinLng: -180,
+		MaxLng: 180,
+	}
+
+	// Градус долготы короче всего на дальней от экватора границе прямоугольника
+	farLat := math.Max(math.Abs(box.MinLat), math.Abs(box.MaxLat))
+	cos := math.Cos(farLat * math.Pi / 180)
+	if box.MinLat > -90 && box.MaxLat < 90 && cos > 0 {
+		dLng := radiusKm / (kmPerDegree * cos)
+		if dLng < 180 {
+			box.MinLng = normalizeLng(center.Lng - dLng)
+			box.MaxLng = normalizeLng(center.Lng + dLng)
+		}
+	}
+	return box
+}
+
+func normalizeLng(lng float64) float64 {
+	for lng < -180 {
+		lng += 360
+	}
+	for lng > 180 {
+		lng -= 360
+	}
+	return lng
+}
@@ -5,13 +5,17 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"time"
 
 	"SpaceBookProject/internal/domain"
+	"SpaceBookProject/internal/repository"
 	"SpaceBookProject/internal/services"
 
 	"github.com/gin-gonic/gin"
 )
 
+const dateLayout = "2006-01-02"
+
 type BookingHandler struct {
 	bookingService *services.BookingService
 }
@@ -41,9 +45,24 @@ func (h *BookingHandler) CreateBooking(c *gin.Context) {
 
 	booking, err := h.bookingService.CreateBooking(userID.(int), &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to create booking: " + err.Error(),
-		})
+		switch err {
+		case repository.ErrSpaceNotFound:
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error: "Space not found",
+			})
+		case services.ErrSpaceInactive:
+			c.JSON(http.StatusConflict, ErrorResponse{
+				Error: "Space is not available for booking",
+			})
+		case services.ErrInvalidDateRange, services.ErrInvalidBookingTime, services.ErrSlotTooShort, services.ErrDateRangeTooLong:
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error: "Failed to create booking: " + err.Error(),
+			})
+		}
 		return
 	}
 
@@ -59,7 +78,18 @@ func (h *BookingHandler) MyBookings(c *gin.Context) {
 		return
 	}
 
-	bookings, err := h.bookingService.ListMyBookings(userID.(int))
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
+
+	bookings, err := h.bookingService.ListMyBookings(userID.(int), page)
+	if err == repository.ErrInvalidCursor {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid cursor",
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error: "Failed to fetch bookings",
@@ -79,7 +109,18 @@ func (h *BookingHandler) OwnerBookings(c *gin.Context) {
 		return
 	}
 
-	bookings, err := h.bookingService.ListOwnerBookings(ownerID.(int))
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
+
+	bookings, err := h.bookingService.ListOwnerBookings(ownerID.(int), page)
+	if err == repository.ErrInvalidCursor {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid cursor",
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error: "Failed to fetch bookings",
@@ -113,33 +154,70 @@ func (h *BookingHandler) CancelBooking(c *gin.Context) {
 		req.Reason = nil
 	}
 
-	if err := h.bookingService.CancelBooking(bookingID, userID.(int), req.Reason); err != nil {
-		switch err {
-		case services.ErrForbidden:
-			c.JSON(http.StatusForbidden, ErrorResponse{
-				Error: "You don't have permission to cancel this booking",
-			})
-		case services.ErrAlreadyStarted:
-			c.JSON(http.StatusBadRequest, ErrorResponse{
-				Error: "Cannot cancel booking that has already started",
-			})
-		case services.ErrWrongStatus:
-			c.JSON(http.StatusBadRequest, ErrorResponse{
-				Error: "Cannot cancel booking with current status",
-			})
-		default:
-			c.JSON(http.StatusInternalServerError, ErrorResponse{
-				Error: "Failed to cancel booking: " + err.Error(),
-			})
-		}
+	refund, err := h.bookingService.CancelBooking(bookingID, userID.(int), req.Reason)
+	if err != nil {
+		writeCancelError(c, err, "Failed to cancel booking")
 		return
 	}
 
-	c.JSON(http.StatusOK, MessageResponse{
-		Message: "Booking cancelled successfully",
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Booking cancelled successfully",
+		"refund":  refund,
 	})
 }
 
+// PreviewCancellation показывает сумму возврата до того, как арендатор подтвердит отмену
+func (h *BookingHandler) PreviewCancellation(c *gin.Context) {
+	bookingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid booking ID",
+		})
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "User not authenticated",
+		})
+		return
+	}
+
+	refund, err := h.bookingService.PreviewCancellation(bookingID, userID.(int))
+	if err != nil {
+		writeCancelError(c, err, "Failed to calculate refund")
+		return
+	}
+
+	c.JSON(http.StatusOK, refund)
+}
+
+func writeCancelError(c *gin.Context, err error, fallback string) {
+	switch err {
+	case repository.ErrBookingNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Booking not found",
+		})
+	case services.ErrForbidden:
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error: "You don't have permission to cancel this booking",
+		})
+	case services.ErrAlreadyStarted:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Cannot cancel booking that has already started",
+		})
+	case services.ErrWrongStatus:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Cannot cancel booking with current status",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: fallback + ": " + err.Error(),
+		})
+	}
+}
+
 func (h *BookingHandler) ApproveBooking(c *gin.Context) {
 	bookingID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -236,6 +314,160 @@ func (h *BookingHandler) RejectBooking(c *gin.Context) {
 	})
 }
 
+func (h *BookingHandler) OwnerCancelBooking(c *gin.Context) {
+	bookingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid booking ID",
+		})
+		return
+	}
+
+	ownerID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "User not authenticated",
+		})
+		return
+	}
+
+	var req domain.OwnerCancelBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Reason is required",
+		})
+		return
+	}
+
+	refund, err := h.bookingService.OwnerCancelBooking(bookingID, ownerID.(int), req.Reason)
+	if err != nil {
+		switch err {
+		case repository.ErrBookingNotFound:
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error: "Booking not found",
+			})
+		case services.ErrReasonRequired:
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Reason is required",
+			})
+		case services.ErrForbidden:
+			c.JSON(http.StatusForbidden, ErrorResponse{
+				Error: "You don't have permission to cancel this booking",
+			})
+		case services.ErrWrongStatus:
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Only approved bookings can be cancelled by the owner",
+			})
+		case services.ErrAlreadyFinished:
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Cannot cancel booking that has already finished",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error: "Failed to cancel booking: " + err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Booking cancelled successfully",
+		"refund":  refund,
+	})
+}
+
+func (h *BookingHandler) CheckIn(c *gin.Context) {
+	h.changeStay(c, h.bookingService.CheckIn)
+}
+
+func (h *BookingHandler) CheckOut(c *gin.Context) {
+	h.changeStay(c, h.bookingService.CheckOut)
+}
+
+func (h *BookingHandler) changeStay(c *gin.Context, action func(id, ownerID int) (*domain.Booking, error)) {
+	bookingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid booking ID",
+		})
+		return
+	}
+
+	ownerID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "User not authenticated",
+		})
+		return
+	}
+
+	booking, err := action(bookingID, ownerID.(int))
+	if err != nil {
+		writeStayError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, booking)
+}
+
+func (h *BookingHandler) MarkNoShow(c *gin.Context) {
+	bookingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid booking ID",
+		})
+		return
+	}
+
+	ownerID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "User not authenticated",
+		})
+		return
+	}
+
+	var req domain.UpdateBookingStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		// Причина опциональна
+		req.Reason = nil
+	}
+
+	if err := h.bookingService.MarkNoShow(bookingID, ownerID.(int), req.Reason); err != nil {
+		writeStayError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{
+		Message: "Booking marked as no-show",
+	})
+}
+
+func writeStayError(c *gin.Context, err error) {
+	switch err {
+	case repository.ErrBookingNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Booking not found",
+		})
+	case services.ErrForbidden:
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error: "You don't have permission to manage this booking",
+		})
+	case services.ErrWrongStatus:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Cannot change booking with current status",
+		})
+	case services.ErrNotStarted, services.ErrNotCheckedIn, services.ErrAlreadyCheckedIn:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: "Failed to update booking: " + err.Error(),
+		})
+	}
+}
+
 func (h *BookingHandler) GetBookingHistory(c *gin.Context) {
 	bookingID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -285,3 +517,410 @@ func (h *BookingHandler) GetBookingHistory(c *gin.Context) {
 		"count":      len(history),
 	})
 }
+
+func (h *BookingHandler) SpaceAvailability(c *gin.Context) {
+	spaceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid space ID",
+		})
+		return
+	}
+
+	// По умолчанию — ближайшие 30 дней начиная с сегодняшнего
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	from, to := today, today.AddDate(0, 0, 30)
+
+	if v := c.Query("from"); v != "" {
+		if from, err = time.Parse(dateLayout, v); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid from date, expected YYYY-MM-DD",
+			})
+			return
+		}
+		if c.Query("to") == "" {
+			to = from.AddDate(0, 0, 30)
+		}
+	}
+	if v := c.Query("to"); v != "" {
+		if to, err = time.Parse(dateLayout, v); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid to date, expected YYYY-MM-DD",
+			})
+			return
+		}
+	}
+	includePending, _ := strconv.ParseBool(c.Query("include_pending"))
+
+	availability, err := h.bookingService.GetAvailability(spaceID, from, to, includePending)
+	if err != nil {
+		switch err {
+		case repository.ErrSpaceNotFound:
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error: "Space not found",
+			})
+		case services.ErrInvalidDateRange, services.ErrDateRangeTooLong:
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error: "Failed to load availability",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, availability)
+}
+
+func (h *BookingHandler) CreateRecurringBooking(c *gin.Context) {
+	var req domain.CreateRecurringBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "User not authenticated",
+		})
+		return
+	}
+
+	details, err := h.bookingService.CreateRecurringBooking(userID.(int), &req)
+	if err != nil {
+		switch err {
+		case services.ErrSeriesConflict:
+			c.JSON(http.StatusConflict, gin.H{
+				"error":     err.Error(),
+				"conflicts": details.Conflicts,
+			})
+		case repository.ErrSpaceNotFound:
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error: "Space not found",
+			})
+		case services.ErrSpaceInactive:
+			c.JSON(http.StatusConflict, ErrorResponse{
+				Error: "Space is not available for booking",
+			})
+		case services.ErrInvalidDateRange, services.ErrInvalidBookingTime, services.ErrSlotTooShort, services.ErrDateRangeTooLong,
+			services.ErrInvalidRecurrence, services.ErrTooManyOccurrences:
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error: "Failed to create booking series: " + err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, details)
+}
+
+func (h *BookingHandler) GetSeries(c *gin.Context) {
+	seriesID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid series ID",
+		})
+		return
+	}
+
+	userIDRaw, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "User not authenticated",
+		})
+		return
+	}
+	roleRaw, exists := c.Get("role")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "User role not found",
+		})
+		return
+	}
+
+	details, err := h.bookingService.GetSeries(seriesID, userIDRaw.(int), domain.UserRole(roleRaw.(string)))
+	if err != nil {
+		writeSeriesError(c, err, "Failed to get booking series")
+		return
+	}
+
+	c.JSON(http.StatusOK, details)
+}
+
+func (h *BookingHandler) ApproveSeries(c *gin.Context) {
+	h.changeSeries(c, h.bookingService.ApproveSeries, "Failed to approve booking series")
+}
+
+func (h *BookingHandler) RejectSeries(c *gin.Context) {
+	h.changeSeries(c, h.bookingService.RejectSeries, "Failed to reject booking series")
+}
+
+func (h *BookingHandler) CancelSeries(c *gin.Context) {
+	h.changeSeries(c, h.bookingService.CancelSeries, "Failed to cancel booking series")
+}
+
+// changeSeries выполняет действие над всеми подходящими повторениями серии.
+// Ошибки по отдельным повторениям возвращаются в conflicts со статусом 200.
+func (h *BookingHandler) changeSeries(
+	c *gin.Context,
+	action func(seriesID, userID int, reason *string) (*domain.BookingSeriesDetails, error),
+	failMessage string,
+) {
+	seriesID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid series ID",
+		})
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "User not authenticated",
+		})
+		return
+	}
+
+	var req domain.UpdateBookingStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		// Причина опциональна
+		req.Reason = nil
+	}
+
+	details, err := action(seriesID, userID.(int), req.Reason)
+	if err != nil {
+		writeSeriesError(c, err, failMessage)
+		return
+	}
+
+	c.JSON(http.StatusOK, details)
+}
+
+func writeSeriesError(c *gin.Context, err error, failMessage string) {
+	switch err {
+	case repository.ErrBookingSeriesNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Booking series not found",
+		})
+	case services.ErrForbidden:
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error: "You don't have access to this booking series",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: failMessage,
+		})
+	}
+}
+
+func (h *BookingHandler) RequestReschedule(c *gin.Context) {
+	bookingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid booking ID",
+		})
+		return
+	}
+
+	var req domain.RescheduleBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "User not authenticated",
+		})
+		return
+	}
+
+	change, err := h.bookingService.RequestReschedule(bookingID, userID.(int), &req)
+	if err != nil {
+		writeRescheduleError(c, err, "Failed to request reschedule")
+		return
+	}
+
+	c.JSON(http.StatusCreated, change)
+}
+
+func (h *BookingHandler) ListReschedules(c *gin.Context) {
+	bookingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid booking ID",
+		})
+		return
+	}
+
+	userIDRaw, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "User not authenticated",
+		})
+		return
+	}
+	roleRaw, exists := c.Get("role")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "User role not found",
+		})
+		return
+	}
+
+	changes, err := h.bookingService.ListReschedules(bookingID, userIDRaw.(int), domain.UserRole(roleRaw.(string)))
+	if err != nil {
+		writeRescheduleError(c, err, "Failed to get reschedule requests")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"booking_id": bookingID,
+		"items":      changes,
+	})
+}
+
+func (h *BookingHandler) WithdrawReschedule(c *gin.Context) {
+	bookingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid booking ID",
+		})
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "User not authenticated",
+		})
+		return
+	}
+
+	if err := h.bookingService.WithdrawReschedule(bookingID, userID.(int)); err != nil {
+		writeRescheduleError(c, err, "Failed to withdraw reschedule request")
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{
+		Message: "Reschedule request withdrawn",
+	})
+}
+
+func (h *BookingHandler) ApproveReschedule(c *gin.Context) {
+	bookingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid booking ID",
+		})
+		return
+	}
+
+	ownerID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "User not authenticated",
+		})
+		return
+	}
+
+	var req domain.UpdateBookingStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		// Причина опциональна
+		req.Reason = nil
+	}
+
+	change, err := h.bookingService.ApproveReschedule(bookingID, ownerID.(int), req.Reason)
+	if err != nil {
+		writeRescheduleError(c, err, "Failed to approve reschedule")
+		return
+	}
+
+	c.JSON(http.StatusOK, change)
+}
+
+func (h *BookingHandler) DeclineReschedule(c *gin.Context) {
+	bookingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid booking ID",
+		})
+		return
+	}
+
+	ownerID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error: "User not authenticated",
+		})
+		return
+	}
+
+	if err := h.bookingService.DeclineReschedule(bookingID, ownerID.(int)); err != nil {
+		writeRescheduleError(c, err, "Failed to decline reschedule")
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{
+		Message: "Reschedule request declined",
+	})
+}
+
+func writeRescheduleError(c *gin.Context, err error, failMessage string) {
+	switch err {
+	case repository.ErrBookingNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Booking not found",
+		})
+	case repository.ErrChangeRequestNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "No pending reschedule request for this booking",
+		})
+	case services.ErrForbidden:
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error: "You don't have access to this booking",
+		})
+	case services.ErrAlreadyStarted:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Cannot reschedule booking that has already started",
+		})
+	case services.ErrWrongStatus, repository.ErrChangeNotApplicable:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Cannot reschedule booking with current status",
+		})
+	case repository.ErrChangeDatesPassed:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Requested dates have already started",
+		})
+	case services.ErrInvalidDateRange, services.ErrInvalidBookingTime, services.ErrSlotTooShort, services.ErrDateRangeTooLong,
+		services.ErrSameDates:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: err.Error(),
+		})
+	case services.ErrOverlappingBooking:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "New dates overlap with another approved booking",
+		})
+	case repository.ErrChangeRequestExists:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Booking already has a pending reschedule request",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error: failMessage,
+		})
+	}
+}
@@ -5,43 +5,19 @@ import (
 	"strconv"
 
 	"SpaceBookProject/internal/domain"
+	"SpaceBookProject/internal/repository"
 	"SpaceBookProject/internal/services"
 
 	"github.com/gin-gonic/gin"
 )
 
 type FavoritesHandler struct {
-	svc *services.FavoritesService
-}
-
-func NewFavoritesHandler(svc *services.FavoritesService) *FavoritesHandler {
-	return &FavoritesHandler{svc: svc}
-}
-
-func getUserID(c *gin.Context) (int, bool) {
-	keys := []string{"user_id", "userID", "userId", "id", "user"}
-	for _, k := range keys {
-		if v, ok := c.Get(k); ok {
-			switch t := v.(type) {
-			case int:
-				return t, true
-			case int64:
-				return int(t), true
-			case float64:
-				return int(t), true
-			case string:
-				n, err := strconv.Atoi(t)
-				if err == nil {
-					return n, true
-				}
-			case domain.User:
-				return t.ID, true
-			case *domain.User:
-				return t.ID, true
-			}
-		}
-	}
-	return 0, false
+	svc    *services.FavoritesService
+	alerts *services.PriceAlertService
+}
+
+func NewFavoritesHandler(svc *services.FavoritesService, alerts *services.PriceAlertService) *FavoritesHandler {
+	return &FavoritesHandler{svc: svc, alerts: alerts}
 }
 
 func (h *FavoritesHandler) AddFavorite(c *gin.Context) {
@@ -51,14 +27,14 @@ func (h *FavoritesHandler) AddFavorite(c *gin.Context) {
 		return
 	}
 
-	userID, ok := getUserID(c)
-	if !ok || userID <= 0 {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
 		return
 	}
 
 	if err := h.svc.Add(c.Request.Context(), userID, spaceID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to add favorite"})
+		writeFavoritesError(c, err, "failed to add favorite")
 		return
 	}
 
@@ -72,14 +48,14 @@ func (h *FavoritesHandler) RemoveFavorite(c *gin.Context) {
 		return
 	}
 
-	userID, ok := getUserID(c)
-	if !ok || userID <= 0 {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
 		return
 	}
 
 	if err := h.svc.Remove(c.Request.Context(), userID, spaceID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove favorite"})
+		writeFavoritesError(c, err, "failed to remove favorite")
 		return
 	}
 
@@ -87,17 +63,262 @@ func (h *FavoritesHandler) RemoveFavorite(c *gin.Context) {
 }
 
 func (h *FavoritesHandler) ListFavorites(c *gin.Context) {
-	userID, ok := getUserID(c)
-	if !ok || userID <= 0 {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	page, ok := parsePage(c)
+	if !ok {
 		return
 	}
 
-	spaces, err := h.svc.List(c.Request.Context(), userID)
+	spaces, err := h.svc.List(c.Request.Context(), userID, page)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list favorites"})
+		writeFavoritesError(c, err, "failed to list favorites")
 		return
 	}
 
 	c.JSON(http.StatusOK, spaces)
 }
+
+func (h *FavoritesHandler) ListCollections(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	collections, err := h.svc.ListCollections(c.Request.Context(), userID)
+	if err != nil {
+		writeFavoritesError(c, err, "failed to list collections")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": collections})
+}
+
+func (h *FavoritesHandler) CreateCollection(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var req domain.CollectionNameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request: " + err.Error()})
+		return
+	}
+
+	collection, err := h.svc.CreateCollection(c.Request.Context(), userID, req.Name)
+	if err != nil {
+		writeFavoritesError(c, err, "failed to create collection")
+		return
+	}
+
+	c.JSON(http.StatusCreated, collection)
+}
+
+func (h *FavoritesHandler) GetCollection(c *gin.Context) {
+	id, userID, ok := collectionParams(c)
+	if !ok {
+		return
+	}
+
+	collection, err := h.svc.GetCollection(c.Request.Context(), id, userID)
+	if err != nil {
+		writeFavoritesError(c, err, "failed to load collection")
+		return
+	}
+
+	c.JSON(http.StatusOK, collection)
+}
+
+// SharedCollection — просмотр подборки по ссылке без авторизации
+func (h *FavoritesHandler) SharedCollection(c *gin.Context) {
+	collection, err := h.svc.GetSharedCollection(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		writeFavoritesError(c, err, "failed to load collection")
+		return
+	}
+
+	c.JSON(http.StatusOK, collection)
+}
+
+func (h *FavoritesHandler) RenameCollection(c *gin.Context) {
+	id, userID, ok := collectionParams(c)
+	if !ok {
+		return
+	}
+
+	var req domain.CollectionNameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request: " + err.Error()})
+		return
+	}
+
+	collection, err := h.svc.RenameCollection(c.Request.Context(), id, userID, req.Name)
+	if err != nil {
+		writeFavoritesError(c, err, "failed to rename collection")
+		return
+	}
+
+	c.JSON(http.StatusOK, collection)
+}
+
+func (h *FavoritesHandler) DeleteCollection(c *gin.Context) {
+	id, userID, ok := collectionParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteCollection(c.Request.Context(), id, userID); err != nil {
+		writeFavoritesError(c, err, "failed to delete collection")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+func (h *FavoritesHandler) AddCollectionSpaces(c *gin.Context) {
+	id, userID, ok := collectionParams(c)
+	if !ok {
+		return
+	}
+
+	var req domain.AddCollectionSpacesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request: " + err.Error()})
+		return
+	}
+
+	collection, err := h.svc.AddToCollection(c.Request.Context(), id, userID, req.SpaceIDs)
+	if err != nil {
+		writeFavoritesError(c, err, "failed to add spaces to collection")
+		return
+	}
+
+	c.JSON(http.StatusOK, collection)
+}
+
+func (h *FavoritesHandler) RemoveCollectionSpace(c *gin.Context) {
+	id, userID, ok := collectionParams(c)
+	if !ok {
+		return
+	}
+
+	spaceID, err := strconv.Atoi(c.Param("spaceId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid space id"})
+		return
+	}
+
+	if err := h.svc.RemoveFromCollection(c.Request.Context(), id, userID, spaceID); err != nil {
+		writeFavoritesError(c, err, "failed to remove space from collection")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+func (h *FavoritesHandler) ShareCollection(c *gin.Context) {
+	id, userID, ok := collectionParams(c)
+	if !ok {
+		return
+	}
+
+	collection, err := h.svc.ShareCollection(c.Request.Context(), id, userID)
+	if err != nil {
+		writeFavoritesError(c, err, "failed to share collection")
+		return
+	}
+
+	c.JSON(http.StatusOK, collection)
+}
+
+func (h *FavoritesHandler) UnshareCollection(c *gin.Context) {
+	id, userID, ok := collectionParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.UnshareCollection(c.Request.Context(), id, userID); err != nil {
+		writeFavoritesError(c, err, "failed to unshare collection")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+func (h *FavoritesHandler) PriceAlertSettings(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	settings, err := h.alerts.Settings(c.Request.Context(), userID)
+	if err != nil {
+		writeFavoritesError(c, err, "failed to load price alert settings")
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+func (h *FavoritesHandler) UpdatePriceAlertSettings(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var req domain.UpdatePriceAlertSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request: " + err.Error()})
+		return
+	}
+
+	settings, err := h.alerts.UpdateSettings(c.Request.Context(), userID, &req)
+	if err != nil {
+		writeFavoritesError(c, err, "failed to update price alert settings")
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// collectionParams читает ID подборки из пути и текущего пользователя; при ошибке уже отвечает клиенту
+func collectionParams(c *gin.Context) (int, int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid collection id"})
+		return 0, 0, false
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return 0, 0, false
+	}
+	return id, userID, true
+}
+
+func writeFavoritesError(c *gin.Context, err error, fallback string) {
+	switch err {
+	case repository.ErrSpaceNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "space not found"})
+	case repository.ErrCollectionNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "collection not found"})
+	case services.ErrForbidden:
+		c.JSON(http.StatusForbidden, gin.H{"error": "you don't own this collection"})
+	case repository.ErrCollectionExists:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case repository.ErrInvalidCursor:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
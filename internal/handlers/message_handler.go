@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"SpaceBookProject/internal/domain"
+	"SpaceBookProject/internal/repository"
+	"SpaceBookProject/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MessageHandler struct {
+	svc *services.MessageService
+}
+
+func NewMessageHandler(svc *services.MessageService) *MessageHandler {
+	return &MessageHandler{svc: svc}
+}
+
+func (h *MessageHandler) ListConversations(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	conversations, err := h.svc.ListConversations(userID)
+	if err != nil {
+		writeMessageError(c, err, "failed to load conversations")
+		return
+	}
+
+	unread := 0
+	for _, conv := range conversations {
+		unread += conv.UnreadCount
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":        conversations,
+		"unread_total": unread,
+	})
+}
+
+func (h *MessageHandler) BookingMessages(c *gin.Context) {
+	bookingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid booking id"})
+		return
+	}
+
+	userID, role, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	messages, err := h.svc.BookingMessages(bookingID, userID, role)
+	if err != nil {
+		writeMessageError(c, err, "failed to load messages")
+		return
+	}
+
+	c.JSON(http.StatusOK, messages)
+}
+
+func (h *MessageHandler) SendBookingMessage(c *gin.Context) {
+	bookingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid booking id"})
+		return
+	}
+
+	userID, role, ok := currentUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var req domain.SendMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request: " + err.Error()})
+		return
+	}
+
+	message, err := h.svc.SendBookingMessage(bookingID, userID, role, req.Body)
+	if err != nil {
+		writeMessageError(c, err, "failed to send message")
+		return
+	}
+
+	c.JSON(http.StatusCreated, message)
+}
+
+func (h *MessageHandler) CreateInquiry(c *gin.Context) {
+	tenantID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var req domain.CreateInquiryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request: " + err.Error()})
+		return
+	}
+
+	message, err := h.svc.CreateInquiry(tenantID, &req)
+	if err != nil {
+		writeMessageError(c, err, "failed to send inquiry")
+		return
+	}
+
+	c.JSON(http.StatusCreated, message)
+}
+
+func (h *MessageHandler) ConversationMessages(c *gin.Context) {
+	conversationID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid conversation id"})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	messages, err := h.svc.ConversationMessages(conversationID, userID)
+	if err != nil {
+		writeMessageError(c, err, "failed to load messages")
+		return
+	}
+
+	c.JSON(http.StatusOK, messages)
+}
+
+func (h *MessageHandler) SendMessage(c *gin.Context) {
+	conversationID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid conversation id"})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var req domain.SendMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request: " + err.Error()})
+		return
+	}
+
+	message, err := h.svc.SendMessage(conversationID, userID, req.Body)
+	if err != nil {
+		writeMessageError(c, err, "failed to send message")
+		return
+	}
+
+	c.JSON(http.StatusCreated, message)
+}
+
+// currentUser достаёт ID и роль пользователя, которые положил AuthMiddleware
+func currentUser(c *gin.Context) (int, domain.UserRole, bool) {
+	id, ok := currentUserID(c)
+	if !ok {
+		return 0, "", false
+	}
+	role, ok := c.Get("role")
+	if !ok {
+		return 0, "", false
+	}
+	roleStr, ok := role.(string)
+	return id, domain.UserRole(roleStr), ok
+}
+
+func writeMessageError(c *gin.Context, err error, fallback string) {
+	switch err {
+	case repository.ErrBookingNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "booking not found"})
+	case repository.ErrSpaceNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "space not found"})
+	case repository.ErrConversationNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "conversation not found"})
+	case services.ErrForbidden:
+		c.JSON(http.StatusForbidden, gin.H{"error": "you are not a participant of this conversation"})
+	case services.ErrSpaceInactive, services.ErrEmptyMessage:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
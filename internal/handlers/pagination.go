@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"SpaceBookProject/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parsePage читает limit и cursor; при ошибке отвечает 400 и возвращает false
+func parsePage(c *gin.Context) (repository.PageRequest, bool) {
+	page := repository.PageRequest{Cursor: c.Query("cursor")}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > repository.MaxPageLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and " + strconv.Itoa(repository.MaxPageLimit)})
+			return page, false
+		}
+		page.Limit = limit
+	}
+	return page, true
+}
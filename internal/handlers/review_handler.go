@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"SpaceBookProject/internal/domain"
+	"SpaceBookProject/internal/repository"
+	"SpaceBookProject/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewHandler struct {
+	svc *services.ReviewService
+}
+
+func NewReviewHandler(svc *services.ReviewService) *ReviewHandler {
+	return &ReviewHandler{svc: svc}
+}
+
+// ReviewSpace — арендатор оценивает пространство по своей брони
+func (h *ReviewHandler) ReviewSpace(c *gin.Context) {
+	h.createReview(c, h.svc.ReviewSpace)
+}
+
+// ReviewTenant — владелец оценивает арендатора по брони в своём пространстве
+func (h *ReviewHandler) ReviewTenant(c *gin.Context) {
+	h.createReview(c, h.svc.ReviewTenant)
+}
+
+func (h *ReviewHandler) createReview(c *gin.Context, create func(bookingID, userID int, req *domain.CreateReviewRequest) (*domain.Review, error)) {
+	bookingID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid booking id"})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var req domain.CreateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request: " + err.Error()})
+		return
+	}
+
+	review, err := create(bookingID, userID, &req)
+	if err != nil {
+		writeReviewError(c, err, "failed to create review")
+		return
+	}
+
+	c.JSON(http.StatusCreated, review)
+}
+
+func (h *ReviewHandler) Reply(c *gin.Context) {
+	reviewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid review id"})
+		return
+	}
+
+	ownerID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var req domain.ReviewReplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request: " + err.Error()})
+		return
+	}
+
+	review, err := h.svc.Reply(reviewID, ownerID, req.Reply)
+	if err != nil {
+		writeReviewError(c, err, "failed to reply to review")
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+func (h *ReviewHandler) ListSpaceReviews(c *gin.Context) {
+	spaceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid space id"})
+		return
+	}
+
+	reviews, err := h.svc.ListSpaceReviews(spaceID)
+	if err != nil {
+		writeReviewError(c, err, "failed to load reviews")
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+func (h *ReviewHandler) GetProfile(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	profile, err := h.svc.GetProfile(userID)
+	if err != nil {
+		writeReviewError(c, err, "failed to load profile")
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+func (h *ReviewHandler) ListUserReviews(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	reviews, err := h.svc.ListUserReviews(userID)
+	if err != nil {
+		writeReviewError(c, err, "failed to load reviews")
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+func writeReviewError(c *gin.Context, err error, fallback string) {
+	switch err {
+	case repository.ErrBookingNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "booking not found"})
+	case repository.ErrSpaceNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "space not found"})
+	case repository.ErrUserNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+	case repository.ErrReviewNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "review not found"})
+	case services.ErrForbidden:
+		c.JSON(http.StatusForbidden, gin.H{"error": "you can't review or reply here"})
+	case services.ErrNotCompleted, services.ErrReviewWindowClosed, services.ErrEmptyReply:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case repository.ErrReviewExists, repository.ErrReplyExists:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"SpaceBookProject/internal/domain"
+	"SpaceBookProject/internal/repository"
+	"SpaceBookProject/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SavedSearchHandler struct {
+	svc *services.SavedSearchService
+}
+
+func NewSavedSearchHandler(svc *services.SavedSearchService) *SavedSearchHandler {
+	return &SavedSearchHandler{svc: svc}
+}
+
+func (h *SavedSearchHandler) List(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	searches, err := h.svc.List(userID)
+	if err != nil {
+		writeSavedSearchError(c, err, "failed to load saved searches")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": searches})
+}
+
+func (h *SavedSearchHandler) Create(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var req domain.CreateSavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request: " + err.Error()})
+		return
+	}
+
+	search, err := h.svc.Create(userID, &req)
+	if err != nil {
+		writeSavedSearchError(c, err, "failed to save search")
+		return
+	}
+
+	c.JSON(http.StatusCreated, search)
+}
+
+func (h *SavedSearchHandler) Pause(c *gin.Context) {
+	h.setPaused(c, true)
+}
+
+func (h *SavedSearchHandler) Resume(c *gin.Context) {
+	h.setPaused(c, false)
+}
+
+func (h *SavedSearchHandler) setPaused(c *gin.Context, paused bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid saved search id"})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	search, err := h.svc.SetPaused(id, userID, paused)
+	if err != nil {
+		writeSavedSearchError(c, err, "failed to update saved search")
+		return
+	}
+
+	c.JSON(http.StatusOK, search)
+}
+
+func (h *SavedSearchHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid saved search id"})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	if err := h.svc.Delete(id, userID); err != nil {
+		writeSavedSearchError(c, err, "failed to delete saved search")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "saved search deleted"})
+}
+
+func writeSavedSearchError(c *gin.Context, err error, fallback string) {
+	switch err {
+	case repository.ErrSavedSearchNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "saved search not found"})
+	case services.ErrForbidden:
+		c.JSON(http.StatusForbidden, gin.H{"error": "you don't own this saved search"})
+	case services.ErrInvalidSearchFilter:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"SpaceBookProject/internal/repository"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"SpaceBookProject/internal/domain"
+	"SpaceBookProject/internal/geo"
 	"SpaceBookProject/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -25,6 +27,7 @@ func (h *SpaceHandler) ListSpaces(c *gin.Context) {
 	maxPriceStr := c.Query("max_price")
 	minAreaStr := c.Query("min_area")
 	maxAreaStr := c.Query("max_area")
+	amenitiesStr := c.Query("amenities")
 
 	var f repository.SpaceFilter
 
@@ -52,13 +55,86 @@ func (h *SpaceHandler) ListSpaces(c *gin.Context) {
 		}
 	}
 
-	spaces, err := h.svc.ListSpaces(f)
+	if amenitiesStr != "" {
+		// Пространство должно иметь все перечисленные удобства
+		f.Amenities = services.NormalizeAmenityCodes(strings.Split(amenitiesStr, ","))
+	}
+
+	fromStr, toStr := c.Query("available_from"), c.Query("available_to")
+	if fromStr != "" || toStr != "" {
+		from, errFrom := services.ParseBookingTime(fromStr)
+		to, errTo := services.ParseBookingTime(toStr)
+		if errFrom != nil || errTo != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "available_from and available_to are required together, expected YYYY-MM-DD or RFC3339"})
+			return
+		}
+		if !from.Before(to) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "available_from must be before available_to"})
+			return
+		}
+		f.AvailableFrom, f.AvailableTo = &from, &to
+	}
+
+	if nearStr := c.Query("near"); nearStr != "" {
+		v, ok := parseFloatList(nearStr, 2)
+		p := geo.Point{Lat: v[0], Lng: v[1]}
+		if !ok || !p.Valid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "near must be lat,lng"})
+			return
+		}
+		f.Near = &p
+		f.RadiusKm = services.DefaultRadiusKm
+		if radiusStr := c.Query("radius_km"); radiusStr != "" {
+			r, err := strconv.ParseFloat(radiusStr, 64)
+			if err != nil || r <= 0 || r > services.MaxRadiusKm {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "radius_km must be between 0 and 500"})
+				return
+			}
+			f.RadiusKm = r
+		}
+	}
+	if bboxStr := c.Query("bbox"); bboxStr != "" {
+		v, ok := parseFloatList(bboxStr, 4)
+		b := geo.Box{MinLat: v[0], MinLng: v[1], MaxLat: v[2], MaxLng: v[3]}
+		if !ok || !b.Valid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bbox must be min_lat,min_lng,max_lat,max_lng"})
+			return
+		}
+		f.Box = &b
+	}
+
+	if sort := c.Query("sort"); sort != "" {
+		f.Sort = repository.SpaceSort(sort)
+	}
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.ListSpaces(f, page)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load spaces"})
+		writeSpaceError(c, err, "failed to load spaces")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"items": spaces})
+	c.JSON(http.StatusOK, result)
+}
+
+// parseFloatList разбирает n чисел через запятую; срез всегда длины n
+func parseFloatList(s string, n int) ([]float64, bool) {
+	out := make([]float64, n)
+	parts := strings.Split(s, ",")
+	if len(parts) != n {
+		return out, false
+	}
+	for i, p := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return out, false
+		}
+		out[i] = v
+	}
+	return out, true
 }
 
 func (h *SpaceHandler) CreateSpace(c *gin.Context) {
@@ -82,9 +158,262 @@ func (h *SpaceHandler) CreateSpace(c *gin.Context) {
 
 	space, err := h.svc.CreateSpace(ownerID, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create space"})
+		writeSpaceError(c, err, "failed to create space")
 		return
 	}
 
 	c.JSON(http.StatusCreated, space)
 }
+
+func (h *SpaceHandler) MySpaces(c *gin.Context) {
+	ownerID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	page, ok := parsePage(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.svc.ListOwnerSpaces(ownerID, page)
+	if err != nil {
+		writeSpaceError(c, err, "failed to load spaces")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *SpaceHandler) UpdateSpace(c *gin.Context) {
+	spaceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid space id"})
+		return
+	}
+
+	var req domain.UpdateSpaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request: " + err.Error()})
+		return
+	}
+
+	ownerID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	space, err := h.svc.UpdateSpace(spaceID, ownerID, &req)
+	if err != nil {
+		writeSpaceError(c, err, "failed to update space")
+		return
+	}
+
+	c.JSON(http.StatusOK, space)
+}
+
+func (h *SpaceHandler) ArchiveSpace(c *gin.Context) {
+	h.changeSpaceState(c, h.svc.ArchiveSpace, "space archived")
+}
+
+func (h *SpaceHandler) UnarchiveSpace(c *gin.Context) {
+	h.changeSpaceState(c, h.svc.UnarchiveSpace, "space restored")
+}
+
+func (h *SpaceHandler) DeleteSpace(c *gin.Context) {
+	h.changeSpaceState(c, h.svc.DeleteSpace, "space deleted")
+}
+
+func (h *SpaceHandler) changeSpaceState(c *gin.Context, action func(id, ownerID int) error, message string) {
+	spaceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid space id"})
+		return
+	}
+
+	ownerID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	if err := action(spaceID, ownerID); err != nil {
+		writeSpaceError(c, err, "failed to change space")
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: message})
+}
+
+func writeSpaceError(c *gin.Context, err error, fallback string) {
+	switch err {
+	case repository.ErrSpaceNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "space not found"})
+	case services.ErrForbidden:
+		c.JSON(http.StatusForbidden, gin.H{"error": "you don't own this space"})
+	case repository.ErrSpaceHasActiveBookings:
+		c.JSON(http.StatusConflict, gin.H{"error": "space has upcoming approved bookings, archive it instead"})
+	case services.ErrInvalidCancellationPolicy, services.ErrInvalidLocation, repository.ErrUnknownAmenity,
+		repository.ErrInvalidCursor, repository.ErrInvalidSort:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// currentUserID достаёт ID пользователя, который положил AuthMiddleware
+func currentUserID(c *gin.Context) (int, bool) {
+	rawID, ok := c.Get("userID")
+	if !ok {
+		return 0, false
+	}
+	id, ok := rawID.(int)
+	return id, ok
+}
+
+func (h *SpaceHandler) QuotePrice(c *gin.Context) {
+	spaceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid space id"})
+		return
+	}
+
+	from, errFrom := services.ParseBookingTime(c.Query("from"))
+	to, errTo := services.ParseBookingTime(c.Query("to"))
+	if errFrom != nil || errTo != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from and to are required, expected YYYY-MM-DD or RFC3339"})
+		return
+	}
+
+	quote, err := h.svc.QuotePrice(spaceID, from, to)
+	if err != nil {
+		switch err {
+		case services.ErrInvalidDateRange, services.ErrInvalidBookingTime, services.ErrSlotTooShort, services.ErrDateRangeTooLong:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			writeSpaceError(c, err, "failed to calculate price")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, quote)
+}
+
+func (h *SpaceHandler) ListPricingRules(c *gin.Context) {
+	spaceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid space id"})
+		return
+	}
+
+	ownerID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	rules, err := h.svc.ListPricingRules(spaceID, ownerID)
+	if err != nil {
+		writeSpaceError(c, err, "failed to load pricing rules")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": rules})
+}
+
+// PriceHistory — история базовой цены пространства для владельца
+func (h *SpaceHandler) PriceHistory(c *gin.Context) {
+	spaceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid space id"})
+		return
+	}
+
+	ownerID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	history, err := h.svc.PriceHistory(spaceID, ownerID)
+	if err != nil {
+		writeSpaceError(c, err, "failed to load price history")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": history})
+}
+
+func (h *SpaceHandler) CreatePricingRule(c *gin.Context) {
+	spaceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid space id"})
+		return
+	}
+
+	var req domain.CreatePricingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request: " + err.Error()})
+		return
+	}
+
+	ownerID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	rule, err := h.svc.CreatePricingRule(spaceID, ownerID, &req)
+	if err != nil {
+		if err == services.ErrInvalidPricingRule || err == services.ErrInvalidDateRange {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		writeSpaceError(c, err, "failed to create pricing rule")
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+func (h *SpaceHandler) DeletePricingRule(c *gin.Context) {
+	spaceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid space id"})
+		return
+	}
+	ruleID, err := strconv.Atoi(c.Param("ruleId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid rule id"})
+		return
+	}
+
+	ownerID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	if err := h.svc.DeletePricingRule(spaceID, ruleID, ownerID); err != nil {
+		if err == repository.ErrPricingRuleNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "pricing rule not found"})
+			return
+		}
+		writeSpaceError(c, err, "failed to delete pricing rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "pricing rule deleted"})
+}
+
+func (h *SpaceHandler) ListAmenities(c *gin.Context) {
+	amenities, err := h.svc.ListAmenities()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load amenities"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"items": amenities})
+}
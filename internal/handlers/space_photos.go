@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"SpaceBookProject/internal/domain"
+	"SpaceBookProject/internal/repository"
+	"SpaceBookProject/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Запас на заголовки и границы частей multipart-формы сверх размера самого фото
+const multipartOverhead = 64 << 10
+
+// UploadPhoto принимает multipart-форму с файлом в поле photo
+func (h *SpaceHandler) UploadPhoto(c *gin.Context) {
+	spaceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid space id"})
+		return
+	}
+
+	ownerID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	// Лимит действует ещё до разбора multipart-формы, а не только при чтении файла
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.svc.MaxPhotoBytes()+multipartOverhead)
+
+	fh, err := c.FormFile("photo")
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": services.ErrPhotoTooLarge.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "photo file is required"})
+		return
+	}
+	f, err := fh.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read photo"})
+		return
+	}
+	defer f.Close()
+
+	// Читаем на байт больше лимита, чтобы сервис мог отличить слишком большой файл
+	data, err := io.ReadAll(io.LimitReader(f, h.svc.MaxPhotoBytes()+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read photo"})
+		return
+	}
+
+	photo, err := h.svc.UploadPhoto(c.Request.Context(), spaceID, ownerID, data)
+	if err != nil {
+		writePhotoError(c, err, "failed to upload photo")
+		return
+	}
+
+	c.JSON(http.StatusCreated, photo)
+}
+
+func (h *SpaceHandler) ListPhotos(c *gin.Context) {
+	spaceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid space id"})
+		return
+	}
+
+	photos, err := h.svc.ListPhotos(spaceID)
+	if err != nil {
+		writePhotoError(c, err, "failed to load photos")
+		return
+	}
+
+	// Фото не больше maxPhotosPerSpace, поэтому они всегда умещаются на одну страницу
+	if photos == nil {
+		photos = []domain.SpacePhoto{}
+	}
+	c.JSON(http.StatusOK, domain.Page[domain.SpacePhoto]{Items: photos, Total: len(photos)})
+}
+
+func (h *SpaceHandler) DeletePhoto(c *gin.Context) {
+	spaceID, photoID, ownerID, ok := photoParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeletePhoto(c.Request.Context(), spaceID, photoID, ownerID); err != nil {
+		writePhotoError(c, err, "failed to delete photo")
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "photo deleted"})
+}
+
+func (h *SpaceHandler) SetCoverPhoto(c *gin.Context) {
+	spaceID, photoID, ownerID, ok := photoParams(c)
+	if !ok {
+		return
+	}
+
+	photos, err := h.svc.SetCoverPhoto(spaceID, photoID, ownerID)
+	if err != nil {
+		writePhotoError(c, err, "failed to set cover photo")
+		return
+	}
+
+	c.JSON(http.StatusOK, photos)
+}
+
+func (h *SpaceHandler) ReorderPhotos(c *gin.Context) {
+	spaceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid space id"})
+		return
+	}
+
+	ownerID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var req domain.ReorderPhotosRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request: " + err.Error()})
+		return
+	}
+
+	photos, err := h.svc.ReorderPhotos(spaceID, ownerID, req.PhotoIDs)
+	if err != nil {
+		writePhotoError(c, err, "failed to reorder photos")
+		return
+	}
+
+	c.JSON(http.StatusOK, photos)
+}
+
+// photoParams разбирает :id, :photoId и текущего пользователя; при ошибке ответ уже записан
+func photoParams(c *gin.Context) (spaceID, photoID, ownerID int, ok bool) {
+	spaceID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid space id"})
+		return 0, 0, 0, false
+	}
+	photoID, err = strconv.Atoi(c.Param("photoId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid photo id"})
+		return 0, 0, 0, false
+	}
+	ownerID, ok = currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return 0, 0, 0, false
+	}
+	return spaceID, photoID, ownerID, true
+}
+
+func writePhotoError(c *gin.Context, err error, fallback string) {
+	switch err {
+	case repository.ErrPhotoNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "photo not found"})
+	case services.ErrPhotoTooLarge, services.ErrPhotoTooManyPixels:
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case services.ErrUnsupportedPhotoType:
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case services.ErrTooManyPhotos, repository.ErrPhotoOrderMismatch:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		writeSpaceError(c, err, fallback)
+	}
+}
@@ -0,0 +1,62 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+)
+
+// Thumbnail уменьшает изображение так, чтобы большая сторона не превышала maxSide,
+// и кодирует результат в JPEG. Маленькие изображения не увеличиваются.
+// Каждый пиксель миниатюры — среднее по соответствующему блоку исходника;
+// прозрачные области накладываются на белый фон, так как в JPEG нет альфа-канала.
+func Thumbnail(src image.Image, maxSide int) ([]byte, error) {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	tw, th := w, h
+	if w > maxSide || h > maxSide {
+		if w >= h {
+			tw, th = maxSide, max(1, h*maxSide/w)
+		} else {
+			tw, th = max(1, w*maxSide/h), maxSide
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		y0 := b.Min.Y + y*h/th
+		y1 := max(y0+1, b.Min.Y+(y+1)*h/th)
+		for x := 0; x < tw; x++ {
+			x0 := b.Min.X + x*w/tw
+			x1 := max(x0+1, b.Min.X+(x+1)*w/tw)
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r += uint64(cr)
+					g += uint64(cg)
+					bl += uint64(cb)
+					a += uint64(ca)
+					n++
+				}
+			}
+			// Цвета в RGBA() уже умножены на альфу, поэтому белый фон просто добавляется
+			bg := 0xffff - a/n
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r/n + bg),
+				G: uint16(g/n + bg),
+				B: uint16(bl/n + bg),
+				A: 0xffff,
+			})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,75 @@
+package pricing
+
+import (
+	"sort"
+	"time"
+
+	"SpaceBookProject/internal/domain"
+)
+
+// Ступени стандартных политик отмены
+var presetTiers = map[domain.CancellationPolicy][]domain.CancellationTier{
+	domain.CancellationFlexible: {{DaysBefore: 1, RefundPct: 100}},
+	domain.CancellationModerate: {{DaysBefore: 5, RefundPct: 100}, {DaysBefore: 1, RefundPct: 50}},
+	domain.CancellationStrict:   {{DaysBefore: 14, RefundPct: 100}, {DaysBefore: 7, RefundPct: 50}},
+}
+
+// PolicyTiers возвращает ступени политики: для custom — переданные, для остальных — стандартные
+func PolicyTiers(policy domain.CancellationPolicy, custom []domain.CancellationTier) []domain.CancellationTier {
+	if policy == domain.CancellationCustom {
+		return custom
+	}
+	return presetTiers[policy]
+}
+
+// Refund считает возврат при отмене брони в момент now. Действует ступень с наибольшим
+// DaysBefore, срок которой ещё не прошёл; если подходящей нет — возврата нет.
+// Ожидающие брони ещё не подтверждены, поэтому их отмена всегда бесплатна.
+func Refund(b *domain.Booking, policy domain.CancellationPolicy, tiers []domain.CancellationTier, now time.Time) *domain.RefundQuote {
+	sorted := make([]domain.CancellationTier, len(tiers))
+	copy(sorted, tiers)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].DaysBefore > sorted[j].DaysBefore })
+
+	pct := 0
+	if b.Status == domain.BookingStatusPending {
+		pct = 100
+	} else {
+		left := b.DateFrom.Sub(now)
+		for _, tier := range sorted {
+			if left >= time.Duration(tier.DaysBefore)*24*time.Hour {
+				pct = tier.RefundPct
+				break
+			}
+		}
+	}
+
+	quote := &domain.RefundQuote{
+		BookingID:    b.ID,
+		Policy:       policy,
+		Tiers:        sorted,
+		RefundPct:    pct,
+		TotalPrice:   b.TotalPrice,
+		Currency:     b.Currency,
+		CalculatedAt: now,
+	}
+	if b.TotalPrice != nil {
+		amount := *b.TotalPrice * pct / 100
+		quote.RefundAmount = &amount
+	}
+	return quote
+}
+
+// OwnerRefundPct — доля возврата при отмене владельцем: до заезда вся стоимость,
+// во время проживания — доля ещё не прошедшего времени, округлённая вверх в пользу арендатора
+func OwnerRefundPct(b *domain.Booking, now time.Time) int {
+	if !now.After(b.DateFrom) {
+		return 100
+	}
+	if !now.Before(b.DateTo) {
+		return 0
+	}
+	// Считаем в секундах: период уже ограничен по длине, переполнения нет
+	total := int64(b.DateTo.Sub(b.DateFrom) / time.Second)
+	left := int64(b.DateTo.Sub(now) / time.Second)
+	return int((left*100 + total - 1) / total)
+}
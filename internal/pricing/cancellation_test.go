@@ -0,0 +1,113 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"SpaceBookProject/internal/domain"
+)
+
+func TestRefund(t *testing.T) {
+	start := time.Date(2026, time.March, 20, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	custom := []domain.CancellationTier{
+		{DaysBefore: 3, RefundPct: 30},
+		{DaysBefore: 10, RefundPct: 80},
+		{DaysBefore: 0, RefundPct: 10},
+	}
+
+	tests := []struct {
+		name    string
+		policy  domain.CancellationPolicy
+		tiers   []domain.CancellationTier
+		status  domain.BookingStatus
+		before  time.Duration
+		wantPct int
+	}{
+		{"pending is always free", domain.CancellationStrict, nil, domain.BookingStatusPending, time.Hour, 100},
+
+		{"flexible one day before", domain.CancellationFlexible, nil, domain.BookingStatusApproved, day, 100},
+		{"flexible just under one day", domain.CancellationFlexible, nil, domain.BookingStatusApproved, day - time.Second, 0},
+
+		{"moderate exactly five days", domain.CancellationModerate, nil, domain.BookingStatusApproved, 5 * day, 100},
+		{"moderate just under five days", domain.CancellationModerate, nil, domain.BookingStatusApproved, 5*day - time.Second, 50},
+		{"moderate exactly one day", domain.CancellationModerate, nil, domain.BookingStatusApproved, day, 50},
+		{"moderate just under one day", domain.CancellationModerate, nil, domain.BookingStatusApproved, day - time.Second, 0},
+
+		{"strict exactly fourteen days", domain.CancellationStrict, nil, domain.BookingStatusApproved, 14 * day, 100},
+		{"strict ten days", domain.CancellationStrict, nil, domain.BookingStatusApproved, 10 * day, 50},
+		{"strict exactly seven days", domain.CancellationStrict, nil, domain.BookingStatusApproved, 7 * day, 50},
+		{"strict three days", domain.CancellationStrict, nil, domain.BookingStatusApproved, 3 * day, 0},
+
+		{"custom above all tiers", domain.CancellationCustom, custom, domain.BookingStatusApproved, 30 * day, 80},
+		{"custom between tiers", domain.CancellationCustom, custom, domain.BookingStatusApproved, 5 * day, 30},
+		{"custom zero day tier before start", domain.CancellationCustom, custom, domain.BookingStatusApproved, time.Hour, 10},
+		{"custom zero day tier at start", domain.CancellationCustom, custom, domain.BookingStatusApproved, 0, 10},
+		{"custom after start", domain.CancellationCustom, custom, domain.BookingStatusApproved, -time.Second, 0},
+		{"custom without tiers", domain.CancellationCustom, nil, domain.BookingStatusApproved, 30 * day, 0},
+
+		{"preset after start", domain.CancellationFlexible, nil, domain.BookingStatusApproved, -day, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			total := 999
+			b := &domain.Booking{ID: 1, Status: tt.status, DateFrom: start, TotalPrice: &total}
+			q := Refund(b, tt.policy, PolicyTiers(tt.policy, tt.tiers), start.Add(-tt.before))
+
+			if q.RefundPct != tt.wantPct {
+				t.Errorf("refund pct = %d, want %d", q.RefundPct, tt.wantPct)
+			}
+			if q.RefundAmount == nil || *q.RefundAmount != total*tt.wantPct/100 {
+				t.Errorf("refund amount = %v, want %d", q.RefundAmount, total*tt.wantPct/100)
+			}
+			for i := 1; i < len(q.Tiers); i++ {
+				if q.Tiers[i-1].DaysBefore < q.Tiers[i].DaysBefore {
+					t.Fatalf("tiers are not sorted by days_before desc: %v", q.Tiers)
+				}
+			}
+		})
+	}
+}
+
+func TestRefundDoesNotReorderTiers(t *testing.T) {
+	tiers := []domain.CancellationTier{{DaysBefore: 1, RefundPct: 50}, {DaysBefore: 7, RefundPct: 100}}
+	b := &domain.Booking{Status: domain.BookingStatusApproved, DateFrom: time.Now().Add(30 * 24 * time.Hour)}
+
+	q := Refund(b, domain.CancellationCustom, tiers, time.Now())
+	if q.RefundPct != 100 {
+		t.Errorf("refund pct = %d, want 100", q.RefundPct)
+	}
+	if q.RefundAmount != nil {
+		t.Errorf("refund amount = %d for booking without price, want nil", *q.RefundAmount)
+	}
+	if tiers[0].DaysBefore != 1 {
+		t.Errorf("caller's tiers were reordered: %v", tiers)
+	}
+}
+
+func TestOwnerRefundPct(t *testing.T) {
+	start := time.Date(2026, time.March, 20, 0, 0, 0, 0, time.UTC)
+	b := &domain.Booking{DateFrom: start, DateTo: start.AddDate(0, 0, 4)}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want int
+	}{
+		{"before start", start.AddDate(0, 0, -1), 100},
+		{"at start", start, 100},
+		{"after one of four nights", start.AddDate(0, 0, 1), 75},
+		{"rounded up for the tenant", start.Add(time.Second), 100},
+		{"half way", start.AddDate(0, 0, 2), 50},
+		{"last second", b.DateTo.Add(-time.Second), 1},
+		{"at end", b.DateTo, 0},
+		{"after end", b.DateTo.Add(time.Hour), 0},
+	}
+	for _, tt := range tests {
+		if got := OwnerRefundPct(b, tt.now); got != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package pricing
+
+import (
+	"errors"
+	"time"
+
+	"SpaceBookProject/internal/domain"
+)
+
+// Минимальная длительность посуточной брони, с которой действуют скидки
+const (
+	WeeklyNights  = 7
+	MonthlyNights = 28
+)
+
+var ErrInvalidPeriod = errors.New("date_from must be before date_to")
+
+// Quote считает стоимость бронирования: сумма цен слотов с учётом правил, минус скидка
+// за неделю/месяц (только для посуточных пространств), плюс разовая плата за уборку.
+// Скидка на уборку не действует.
+func Quote(space *domain.Space, rules []domain.PricingRule, from, to time.Time) (*domain.PriceQuote, error) {
+	if !from.Before(to) {
+		return nil, ErrInvalidPeriod
+	}
+
+	calendar := NewCalendar(space, rules)
+	step := SlotDuration(space.BookingUnit)
+	units := Units(space.BookingUnit, from, to)
+	breakdown := make([]domain.SlotPrice, 0, units)
+	subtotal := 0
+	for i := 0; i < units; i++ {
+		slot := calendar.PriceFor(from.Add(time.Duration(i) * step))
+		breakdown = append(breakdown, slot)
+		subtotal += slot.Price
+	}
+
+	discountPct := 0
+	if space.BookingUnit != domain.BookingUnitHour {
+		switch {
+		case units >= MonthlyNights && space.MonthlyDiscountPct > 0:
+			discountPct = space.MonthlyDiscountPct
+		case units >= WeeklyNights && space.WeeklyDiscountPct > 0:
+			discountPct = space.WeeklyDiscountPct
+		}
+	}
+	discount := subtotal * discountPct / 100
+
+	return &domain.PriceQuote{
+		SpaceID:     space.ID,
+		DateFrom:    from,
+		DateTo:      to,
+		Unit:        space.BookingUnit,
+		Units:       units,
+		UnitPrice:   space.Price,
+		Breakdown:   breakdown,
+		Subtotal:    subtotal,
+		DiscountPct: discountPct,
+		Discount:    discount,
+		CleaningFee: space.CleaningFee,
+		Total:       subtotal - discount + space.CleaningFee,
+		Currency:    space.Currency,
+	}, nil
+}
+
+// SlotDuration возвращает длительность одного слота бронирования
+func SlotDuration(unit domain.BookingUnit) time.Duration {
+	if unit == domain.BookingUnitHour {
+		return time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// Units возвращает количество оплачиваемых слотов; неполный час считается целым.
+// Период должен быть уже проверен на длину: to.Sub(from) насыщается на сотнях лет.
+func Units(unit domain.BookingUnit, from, to time.Time) int {
+	step := SlotDuration(unit)
+	d := to.Sub(from)
+	units := d / step
+	if d%step != 0 {
+		units++
+	}
+	return int(units)
+}
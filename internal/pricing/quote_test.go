@@ -0,0 +1,241 @@
+package pricing
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"SpaceBookProject/internal/domain"
+)
+
+func intPtr(v int) *int { return &v }
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func datePtr(y int, m time.Month, d int) *time.Time {
+	t := date(y, m, d)
+	return &t
+}
+
+func TestQuote(t *testing.T) {
+	// 2026-03-02 — понедельник
+	monday := date(2026, time.March, 2)
+	daily := func(weekly, monthly int) *domain.Space {
+		return &domain.Space{
+			ID:                 1,
+			Price:              1000,
+			BookingUnit:        domain.BookingUnitDay,
+			CleaningFee:        500,
+			WeeklyDiscountPct:  weekly,
+			MonthlyDiscountPct: monthly,
+			Currency:           "KZT",
+		}
+	}
+	hourly := &domain.Space{ID: 2, Price: 200, BookingUnit: domain.BookingUnitHour, WeeklyDiscountPct: 10, Currency: "KZT"}
+
+	weekend := domain.PricingRule{ID: 1, DaysOfWeek: []int{5, 6}, AdjustPct: intPtr(50)}
+
+	tests := []struct {
+		name     string
+		space    *domain.Space
+		rules    []domain.PricingRule
+		from, to time.Time
+
+		wantUnits       int
+		wantSlots       []int
+		wantSubtotal    int
+		wantDiscountPct int
+		wantTotal       int
+	}{
+		{
+			name:         "base price and cleaning fee",
+			space:        daily(10, 20),
+			from:         monday,
+			to:           monday.AddDate(0, 0, 3),
+			wantUnits:    3,
+			wantSlots:    []int{1000, 1000, 1000},
+			wantSubtotal: 3000,
+			wantTotal:    3500,
+		},
+		{
+			name:         "weekend adjustment",
+			space:        daily(0, 0),
+			rules:        []domain.PricingRule{weekend},
+			from:         monday.AddDate(0, 0, 3),
+			to:           monday.AddDate(0, 0, 6),
+			wantUnits:    3,
+			wantSlots:    []int{1000, 1500, 1500},
+			wantSubtotal: 4000,
+			wantTotal:    4500,
+		},
+		{
+			name:  "higher priority wins",
+			space: daily(0, 0),
+			rules: []domain.PricingRule{
+				{ID: 1, Price: intPtr(3000), Priority: 10},
+				{ID: 2, Price: intPtr(2000), Priority: 1},
+			},
+			from:         monday,
+			to:           monday.AddDate(0, 0, 1),
+			wantUnits:    1,
+			wantSlots:    []int{3000},
+			wantSubtotal: 3000,
+			wantTotal:    3500,
+		},
+		{
+			name:  "equal priority prefers newer rule",
+			space: daily(0, 0),
+			rules: []domain.PricingRule{
+				{ID: 2, Price: intPtr(2500)},
+				{ID: 1, Price: intPtr(2000)},
+			},
+			from:         monday,
+			to:           monday.AddDate(0, 0, 1),
+			wantUnits:    1,
+			wantSlots:    []int{2500},
+			wantSubtotal: 2500,
+			wantTotal:    3000,
+		},
+		{
+			name:  "rule limited by dates",
+			space: daily(0, 0),
+			rules: []domain.PricingRule{
+				{ID: 1, DateFrom: datePtr(2026, time.March, 3), DateTo: datePtr(2026, time.March, 3), Price: intPtr(5000)},
+			},
+			from:         monday,
+			to:           monday.AddDate(0, 0, 3),
+			wantUnits:    3,
+			wantSlots:    []int{1000, 5000, 1000},
+			wantSubtotal: 7000,
+			wantTotal:    7500,
+		},
+		{
+			name:         "six nights get no weekly discount",
+			space:        daily(10, 20),
+			from:         monday,
+			to:           monday.AddDate(0, 0, WeeklyNights-1),
+			wantUnits:    6,
+			wantSubtotal: 6000,
+			wantTotal:    6500,
+		},
+		{
+			name:            "weekly discount from seven nights",
+			space:           daily(10, 20),
+			rules:           []domain.PricingRule{weekend},
+			from:            monday,
+			to:              monday.AddDate(0, 0, WeeklyNights),
+			wantUnits:       7,
+			wantSubtotal:    8000,
+			wantDiscountPct: 10,
+			wantTotal:       8000 - 800 + 500,
+		},
+		{
+			name:            "weekly discount up to 27 nights",
+			space:           daily(10, 20),
+			from:            monday,
+			to:              monday.AddDate(0, 0, MonthlyNights-1),
+			wantUnits:       27,
+			wantSubtotal:    27000,
+			wantDiscountPct: 10,
+			wantTotal:       27000 - 2700 + 500,
+		},
+		{
+			name:            "monthly discount from 28 nights",
+			space:           daily(10, 20),
+			from:            monday,
+			to:              monday.AddDate(0, 0, MonthlyNights),
+			wantUnits:       28,
+			wantSubtotal:    28000,
+			wantDiscountPct: 20,
+			wantTotal:       28000 - 5600 + 500,
+		},
+		{
+			name:            "weekly discount when there is no monthly one",
+			space:           daily(10, 0),
+			from:            monday,
+			to:              monday.AddDate(0, 0, MonthlyNights),
+			wantUnits:       28,
+			wantSubtotal:    28000,
+			wantDiscountPct: 10,
+			wantTotal:       28000 - 2800 + 500,
+		},
+		{
+			name:         "partial hour is charged in full",
+			space:        hourly,
+			from:         monday.Add(10 * time.Hour),
+			to:           monday.Add(12*time.Hour + 30*time.Minute),
+			wantUnits:    3,
+			wantSlots:    []int{200, 200, 200},
+			wantSubtotal: 600,
+			wantTotal:    600,
+		},
+		{
+			name:         "hourly bookings get no long stay discount",
+			space:        hourly,
+			from:         monday.Add(8 * time.Hour),
+			to:           monday.Add(18 * time.Hour),
+			wantUnits:    10,
+			wantSubtotal: 2000,
+			wantTotal:    2000,
+		},
+		{
+			name:  "hourly slots priced by their own day",
+			space: hourly,
+			rules: []domain.PricingRule{
+				{ID: 1, DateFrom: datePtr(2026, time.March, 3), Price: intPtr(300)},
+			},
+			from:         monday.Add(22 * time.Hour),
+			to:           monday.Add(26 * time.Hour),
+			wantUnits:    4,
+			wantSlots:    []int{200, 200, 300, 300},
+			wantSubtotal: 1000,
+			wantTotal:    1000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Quote(tt.space, tt.rules, tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("Quote: %v", err)
+			}
+			if q.Units != tt.wantUnits {
+				t.Errorf("units = %d, want %d", q.Units, tt.wantUnits)
+			}
+			if tt.wantSlots != nil {
+				slots := make([]int, len(q.Breakdown))
+				for i, s := range q.Breakdown {
+					slots[i] = s.Price
+				}
+				if !slices.Equal(slots, tt.wantSlots) {
+					t.Errorf("slots = %v, want %v", slots, tt.wantSlots)
+				}
+			}
+			if q.Subtotal != tt.wantSubtotal {
+				t.Errorf("subtotal = %d, want %d", q.Subtotal, tt.wantSubtotal)
+			}
+			if q.DiscountPct != tt.wantDiscountPct {
+				t.Errorf("discount pct = %d, want %d", q.DiscountPct, tt.wantDiscountPct)
+			}
+			if q.CleaningFee != tt.space.CleaningFee {
+				t.Errorf("cleaning fee = %d, want %d", q.CleaningFee, tt.space.CleaningFee)
+			}
+			if q.Total != tt.wantTotal {
+				t.Errorf("total = %d, want %d", q.Total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestQuoteInvalidPeriod(t *testing.T) {
+	space := &domain.Space{Price: 1000, BookingUnit: domain.BookingUnitDay}
+	from := date(2026, time.March, 2)
+	for _, to := range []time.Time{from, from.AddDate(0, 0, -1)} {
+		if _, err := Quote(space, nil, from, to); !errors.Is(err, ErrInvalidPeriod) {
+			t.Errorf("Quote(%s, %s): got %v, want ErrInvalidPeriod", from, to, err)
+		}
+	}
+}
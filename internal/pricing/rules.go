@@ -0,0 +1,71 @@
+package pricing
+
+import (
+	"sort"
+	"time"
+
+	"SpaceBookProject/internal/domain"
+)
+
+// Calendar определяет цену каждого слота по базовой цене пространства и его правилам
+type Calendar struct {
+	basePrice int
+	rules     []domain.PricingRule
+}
+
+func NewCalendar(space *domain.Space, rules []domain.PricingRule) *Calendar {
+	sorted := make([]domain.PricingRule, len(rules))
+	copy(sorted, rules)
+	// Сначала самые приоритетные, при равном приоритете — более новые
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Priority != sorted[j].Priority {
+			return sorted[i].Priority > sorted[j].Priority
+		}
+		return sorted[i].ID > sorted[j].ID
+	})
+	return &Calendar{basePrice: space.Price, rules: sorted}
+}
+
+// PriceFor возвращает цену слота, начинающегося в момент start
+func (c *Calendar) PriceFor(start time.Time) domain.SlotPrice {
+	for _, rule := range c.rules {
+		if !Matches(rule, start) {
+			continue
+		}
+		id := rule.ID
+		return domain.SlotPrice{Start: start, Price: apply(rule, c.basePrice), RuleID: &id}
+	}
+	return domain.SlotPrice{Start: start, Price: c.basePrice}
+}
+
+// Matches проверяет, действует ли правило в момент at. Сравниваются календарные
+// даты в часовом поясе самого момента, поэтому для почасовых броней важен их offset.
+func Matches(rule domain.PricingRule, at time.Time) bool {
+	day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+	if rule.DateFrom != nil && day.Before(*rule.DateFrom) {
+		return false
+	}
+	if rule.DateTo != nil && day.After(*rule.DateTo) {
+		return false
+	}
+	if len(rule.DaysOfWeek) == 0 {
+		return true
+	}
+	weekday := int(day.Weekday())
+	for _, d := range rule.DaysOfWeek {
+		if d == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+func apply(rule domain.PricingRule, basePrice int) int {
+	if rule.Price != nil {
+		return *rule.Price
+	}
+	if rule.AdjustPct != nil {
+		return basePrice + basePrice*(*rule.AdjustPct)/100
+	}
+	return basePrice
+}
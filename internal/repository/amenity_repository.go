@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"SpaceBookProject/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+var ErrUnknownAmenity = errors.New("unknown amenity code")
+
+type AmenityRepository struct {
+	db *sql.DB
+}
+
+func NewAmenityRepository(db *sql.DB) *AmenityRepository {
+	return &AmenityRepository{db: db}
+}
+
+func (r *AmenityRepository) List() ([]domain.Amenity, error) {
+	rows, err := r.db.Query(`SELECT id, code, name FROM amenities ORDER BY name, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.Amenity
+	for rows.Next() {
+		var a domain.Amenity
+		if err := rows.Scan(&a.ID, &a.Code, &a.Name); err != nil {
+			return nil, err
+		}
+		res = append(res, a)
+	}
+	return res, rows.Err()
+}
+
+// setSpaceAmenities заменяет набор удобств пространства внутри транзакции, в которой
+// сохраняется само пространство. Неизвестный код — ErrUnknownAmenity: транзакция
+// откатывается целиком, и пространство не остаётся без удобств или с устаревшими.
+func setSpaceAmenities(tx *sql.Tx, spaceID int, codes []string) error {
+	if _, err := tx.Exec(`DELETE FROM space_amenities WHERE space_id = $1`, spaceID); err != nil {
+		return err
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+
+	const insertQuery = `
+		INSERT INTO space_amenities (space_id, amenity_id)
+		SELECT $1, id FROM amenities WHERE code = ANY($2)`
+
+	res, err := tx.Exec(insertQuery, spaceID, pq.Array(codes))
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if int(n) != len(codes) {
+		return ErrUnknownAmenity
+	}
+	return nil
+}
+
+// ListBySpaces загружает удобства сразу для нескольких пространств
+func (r *AmenityRepository) ListBySpaces(spaceIDs []int) (map[int][]domain.Amenity, error) {
+	res := make(map[int][]domain.Amenity, len(spaceIDs))
+	if len(spaceIDs) == 0 {
+		return res, nil
+	}
+
+	ids := make(pq.Int64Array, len(spaceIDs))
+	for i, id := range spaceIDs {
+		ids[i] = int64(id)
+	}
+
+	const query = `
+		SELECT sa.space_id, a.id, a.code, a.name
+		FROM space_amenities sa
+		JOIN amenities a ON a.id = sa.amenity_id
+		WHERE sa.space_id = ANY($1)
+		ORDER BY sa.space_id, a.name, a.id`
+
+	rows, err := r.db.Query(query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			spaceID int
+			a       domain.Amenity
+		)
+		if err := rows.Scan(&spaceID, &a.ID, &a.Code, &a.Name); err != nil {
+			return nil, err
+		}
+		res[spaceID] = append(res[spaceID], a)
+	}
+	return res, rows.Err()
+}
@@ -0,0 +1,278 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"SpaceBookProject/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrChangeRequestNotFound = errors.New("booking change request not found")
+	ErrChangeRequestExists   = errors.New("booking already has a pending change request")
+	ErrChangeNotApplicable   = errors.New("booking can no longer be rescheduled")
+	ErrChangeDatesPassed     = errors.New("requested dates have already started")
+)
+
+const changeRequestColumns = `id, booking_id, requested_by, old_date_from, old_date_to, new_date_from, new_date_to,
+	total_price, currency, status, reason, decided_by, decided_at, created_at`
+
+func scanChangeRequest(row rowScanner, cr *domain.BookingChangeRequest) error {
+	return row.Scan(
+		&cr.ID, &cr.BookingID, &cr.RequestedBy,
+		&cr.OldDateFrom, &cr.OldDateTo, &cr.NewDateFrom, &cr.NewDateTo,
+		&cr.TotalPrice, &cr.Currency, &cr.Status, &cr.Reason,
+		&cr.DecidedBy, &cr.DecidedAt, &cr.CreatedAt,
+	)
+}
+
+// CreateChangeRequest сохраняет заявку на перенос и ставит событие reschedule_requested в outbox
+func (r *BookingRepository) CreateChangeRequest(cr *domain.BookingChangeRequest) (err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	const query = `
+		INSERT INTO booking_change_requests
+		(booking_id, requested_by, old_date_from, old_date_to, new_date_from, new_date_to, total_price, currency, reason)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, status, created_at`
+
+	err = tx.QueryRow(
+		query,
+		cr.BookingID,
+		cr.RequestedBy,
+		cr.OldDateFrom,
+		cr.OldDateTo,
+		cr.NewDateFrom,
+		cr.NewDateTo,
+		cr.TotalPrice,
+		cr.Currency,
+		cr.Reason,
+	).Scan(&cr.ID, &cr.Status, &cr.CreatedAt)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		err = ErrChangeRequestExists
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = enqueueBookingEventTx(tx, domain.BookingEventRescheduleRequested, cr.BookingID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetPendingChangeRequest возвращает нерассмотренную заявку на перенос брони
+func (r *BookingRepository) GetPendingChangeRequest(bookingID int) (*domain.BookingChangeRequest, error) {
+	query := `SELECT ` + changeRequestColumns + `
+		FROM booking_change_requests
+		WHERE booking_id = $1 AND status = 'pending'`
+
+	cr := &domain.BookingChangeRequest{}
+	err := scanChangeRequest(r.db.QueryRow(query, bookingID), cr)
+	if err == sql.ErrNoRows {
+		return nil, ErrChangeRequestNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cr, nil
+}
+
+func (r *BookingRepository) ListChangeRequests(bookingID int) ([]domain.BookingChangeRequest, error) {
+	query := `SELECT ` + changeRequestColumns + `
+		FROM booking_change_requests
+		WHERE booking_id = $1
+		ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.db.Query(query, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.BookingChangeRequest
+	for rows.Next() {
+		var cr domain.BookingChangeRequest
+		if err := scanChangeRequest(rows, &cr); err != nil {
+			return nil, err
+		}
+		res = append(res, cr)
+	}
+	return res, rows.Err()
+}
+
+// ApplyChangeRequest переносит бронь на даты из заявки. В одной транзакции повторно
+// проверяется, что новые даты ещё не наступили и не пересекаются с одобренными бронями,
+// обновляется бронь, пишется история со старым и новым периодом, закрывается заявка
+// и ставится событие в outbox. Для одобренной брони, как и в Approve, отклоняются
+// ожидающие заявки на новые даты.
+func (r *BookingRepository) ApplyChangeRequest(changeID, decidedBy int, reason *string) (*domain.BookingChangeRequest, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	cr := &domain.BookingChangeRequest{}
+	query := `SELECT ` + changeRequestColumns + `
+		FROM booking_change_requests
+		WHERE id = $1 AND status = 'pending'
+		FOR UPDATE`
+	err = scanChangeRequest(tx.QueryRow(query, changeID), cr)
+	if err == sql.ErrNoRows {
+		err = ErrChangeRequestNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Пространство блокируется раньше брони в том же порядке, что и в Approve
+	var (
+		spaceID, tenantID int
+		status            domain.BookingStatus
+	)
+	const lockSpace = `
+		SELECT s.id
+		FROM bookings b
+		JOIN spaces s ON s.id = b.space_id
+		WHERE b.id = $1
+		FOR NO KEY UPDATE OF s`
+	if err = tx.QueryRow(lockSpace, cr.BookingID).Scan(&spaceID); err != nil {
+		return nil, err
+	}
+	err = tx.QueryRow(`SELECT tenant_id, status FROM bookings WHERE id = $1 FOR UPDATE`, cr.BookingID).
+		Scan(&tenantID, &status)
+	if err != nil {
+		return nil, err
+	}
+	if status != domain.BookingStatusPending && status != domain.BookingStatusApproved {
+		err = ErrChangeNotApplicable
+		return nil, err
+	}
+	// Заявка могла пролежать до наступления новых дат
+	if !cr.NewDateFrom.After(time.Now()) {
+		err = ErrChangeDatesPassed
+		return nil, err
+	}
+
+	overlap, err := hasApprovedOverlap(tx, spaceID, cr.NewDateFrom, cr.NewDateTo, &cr.BookingID)
+	if err != nil {
+		return nil, err
+	}
+	if overlap {
+		err = ErrOverlappingBooking
+		return nil, err
+	}
+
+	const updateBooking = `
+		UPDATE bookings
+		SET date_from = $1, date_to = $2, total_price = $3, currency = $4, updated_at = NOW()
+		WHERE id = $5`
+	if _, err = tx.Exec(updateBooking, cr.NewDateFrom, cr.NewDateTo, cr.TotalPrice, cr.Currency, cr.BookingID); err != nil {
+		err = mapBookingError(err)
+		return nil, err
+	}
+
+	now := time.Now()
+	history := &domain.BookingStatusHistory{
+		BookingID:   cr.BookingID,
+		OldStatus:   &status,
+		NewStatus:   status,
+		ChangedBy:   &decidedBy,
+		Actor:       domain.HistoryActorOwner,
+		ChangedAt:   now,
+		Reason:      reason,
+		OldDateFrom: &cr.OldDateFrom,
+		OldDateTo:   &cr.OldDateTo,
+		NewDateFrom: &cr.NewDateFrom,
+		NewDateTo:   &cr.NewDateTo,
+	}
+	if err = NewBookingStatusHistoryRepository(tx).Create(history); err != nil {
+		return nil, err
+	}
+
+	if status == domain.BookingStatusApproved {
+		if _, err = rejectCompetingTx(tx, cr.BookingID, decidedBy); err != nil {
+			return nil, err
+		}
+	}
+
+	const closeQuery = `
+		UPDATE booking_change_requests
+		SET status = 'approved', decided_by = $1, decided_at = $2
+		WHERE id = $3`
+	if _, err = tx.Exec(closeQuery, decidedBy, now, changeID); err != nil {
+		return nil, err
+	}
+
+	err = enqueueEventTx(tx, domain.BookingEvent{
+		Type:      domain.BookingEventRescheduleApproved,
+		BookingID: cr.BookingID,
+		SpaceID:   spaceID,
+		TenantID:  tenantID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	cr.Status = domain.BookingChangeApproved
+	cr.DecidedBy = &decidedBy
+	cr.DecidedAt = &now
+	return cr, nil
+}
+
+// CloseChangeRequest закрывает нерассмотренную заявку без переноса (declined или withdrawn).
+// Отклонение владельцем ставит событие reschedule_declined в outbox.
+func (r *BookingRepository) CloseChangeRequest(changeID int, status domain.BookingChangeStatus, decidedBy int) (err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	const query = `
+		UPDATE booking_change_requests
+		SET status = $1, decided_by = $2, decided_at = NOW()
+		WHERE id = $3 AND status = 'pending'
+		RETURNING booking_id`
+
+	var bookingID int
+	err = tx.QueryRow(query, status, decidedBy, changeID).Scan(&bookingID)
+	if err == sql.ErrNoRows {
+		err = ErrChangeRequestNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if status == domain.BookingChangeDeclined {
+		if err = enqueueBookingEventTx(tx, domain.BookingEventRescheduleDeclined, bookingID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
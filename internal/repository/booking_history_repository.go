@@ -14,18 +14,18 @@ func NewBookingHistoryRepository(db *sql.DB) *BookingHistoryRepository {
 	return &BookingHistoryRepository{db: db}
 }
 
-func (r *BookingHistoryRepository) Add(bookingID int, oldStatus *string, newStatus string, changedBy int) error {
+func (r *BookingHistoryRepository) Add(bookingID int, oldStatus *string, newStatus string, changedBy *int, actor domain.HistoryActor) error {
 	const q = `
-  INSERT INTO booking_status_history (booking_id, old_status, new_status, changed_by)
-  VALUES ($1, $2, $3, $4)
+  INSERT INTO booking_status_history (booking_id, old_status, new_status, changed_by, actor)
+  VALUES ($1, $2, $3, $4, $5)
  `
-	_, err := r.db.Exec(q, bookingID, oldStatus, newStatus, changedBy)
+	_, err := r.db.Exec(q, bookingID, oldStatus, newStatus, changedBy, actor)
 	return err
 }
 
 func (r *BookingHistoryRepository) List(bookingID int) ([]domain.BookingStatusHistory, error) {
 	const q = `
-  SELECT id, booking_id, old_status, new_status, changed_by, changed_at
+  SELECT id, booking_id, old_status, new_status, changed_by, actor, changed_at
   FROM booking_status_history
   WHERE booking_id = $1
   ORDER BY changed_at ASC, id ASC
@@ -40,7 +40,7 @@ func (r *BookingHistoryRepository) List(bookingID int) ([]domain.BookingStatusHi
 	var res []domain.BookingStatusHistory
 	for rows.Next() {
 		var h domain.BookingStatusHistory
-		if err := rows.Scan(&h.ID, &h.BookingID, &h.OldStatus, &h.NewStatus, &h.ChangedBy, &h.ChangedAt); err != nil {
+		if err := rows.Scan(&h.ID, &h.BookingID, &h.OldStatus, &h.NewStatus, &h.ChangedBy, &h.Actor, &h.ChangedAt); err != nil {
 			return nil, err
 		}
 		res = append(res, h)
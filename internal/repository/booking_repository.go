@@ -3,15 +3,68 @@ package repository
 import (
 	"database/sql"
 	"errors"
+	"fmt"
+	"slices"
 	"time"
 
 	"SpaceBookProject/internal/domain"
+
+	"github.com/lib/pq"
 )
 
 var (
-	ErrBookingNotFound = errors.New("booking not found")
+	ErrBookingNotFound    = errors.New("booking not found")
+	ErrOverlappingBooking = errors.New("overlapping approved booking")
+	// Текущий статус брони не допускает запрошенного перехода
+	ErrInvalidStatusTransition = errors.New("invalid booking status")
+	// Бронь не одобрена или заезд по ней уже отмечен
+	ErrBookingNotCheckable = errors.New("booking cannot be checked in")
 )
 
+// Ограничение из миграции 0006, которое не даёт одобрить пересекающиеся брони
+const noApprovedOverlapConstraint = "bookings_no_approved_overlap"
+
+// mapBookingError переводит нарушение exclusion-ограничения в ErrOverlappingBooking
+func mapBookingError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) &&
+		pqErr.Code == "23P01" &&
+		pqErr.Constraint == noApprovedOverlapConstraint {
+		return ErrOverlappingBooking
+	}
+	return err
+}
+
+const bookingColumns = `id, space_id, tenant_id, date_from, date_to, status, total_price, currency, series_id,
+	cancellation_policy, cancellation_tiers, refund_pct, refund_amount, checked_in_at, checked_out_at,
+	created_at, updated_at`
+
+func scanBooking(row rowScanner, b *domain.Booking) error {
+	return row.Scan(
+		&b.ID, &b.SpaceID, &b.TenantID,
+		&b.DateFrom, &b.DateTo, &b.Status,
+		&b.TotalPrice, &b.Currency, &b.SeriesID,
+		&b.CancellationPolicy, tiersJSON{&b.CancellationTiers}, &b.RefundPct, &b.RefundAmount,
+		&b.CheckedInAt, &b.CheckedOutAt,
+		&b.CreatedAt, &b.UpdatedAt,
+	)
+}
+
+// collectBookings читает все строки и закрывает rows
+func collectBookings(rows *sql.Rows) ([]domain.Booking, error) {
+	defer rows.Close()
+
+	var res []domain.Booking
+	for rows.Next() {
+		var b domain.Booking
+		if err := scanBooking(rows, &b); err != nil {
+			return nil, err
+		}
+		res = append(res, b)
+	}
+	return res, rows.Err()
+}
+
 type BookingRepository struct {
 	db          *sql.DB
 	historyRepo *BookingStatusHistoryRepository
@@ -26,12 +79,6 @@ func NewBookingRepository(db *sql.DB) *BookingRepository {
 }
 
 func (r *BookingRepository) Create(b *domain.Booking) error {
-	const query = `
-		INSERT INTO bookings (space_id, tenant_id, date_from, date_to, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
-		RETURNING id, status, created_at, updated_at;
-	`
-
 	tx, err := r.db.Begin()
 	if err != nil {
 		return err
@@ -42,13 +89,35 @@ func (r *BookingRepository) Create(b *domain.Booking) error {
 		}
 	}()
 
-	err = tx.QueryRow(
+	if err = insertBookingTx(tx, b); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// insertBookingTx создаёт бронь, записывает её начальный статус в историю
+// и ставит событие created в outbox
+func insertBookingTx(tx *sql.Tx, b *domain.Booking) error {
+	const query = `
+		INSERT INTO bookings (space_id, tenant_id, date_from, date_to, status, total_price, currency, series_id,
+		                      cancellation_policy, cancellation_tiers, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW(), NOW())
+		RETURNING id, status, created_at, updated_at;
+	`
+
+	err := tx.QueryRow(
 		query,
 		b.SpaceID,
 		b.TenantID,
 		b.DateFrom,
 		b.DateTo,
 		b.Status,
+		b.TotalPrice,
+		b.Currency,
+		b.SeriesID,
+		b.CancellationPolicy,
+		tiersJSON{&b.CancellationTiers},
 	).Scan(&b.ID, &b.Status, &b.CreatedAt, &b.UpdatedAt)
 
 	if err != nil {
@@ -61,21 +130,19 @@ func (r *BookingRepository) Create(b *domain.Booking) error {
 		return err
 	}
 
-	return tx.Commit()
+	return enqueueEventTx(tx, domain.BookingEvent{
+		Type:      domain.BookingEventCreated,
+		BookingID: b.ID,
+		SpaceID:   b.SpaceID,
+		TenantID:  b.TenantID,
+	})
 }
 
 func (r *BookingRepository) GetByID(id int) (*domain.Booking, error) {
-	const q = `
-		SELECT id, space_id, tenant_id, date_from, date_to, status, created_at, updated_at
-		FROM bookings
-		WHERE id = $1`
+	q := `SELECT ` + bookingColumns + ` FROM bookings WHERE id = $1`
 
 	b := &domain.Booking{}
-	err := r.db.QueryRow(q, id).Scan(
-		&b.ID, &b.SpaceID, &b.TenantID,
-		&b.DateFrom, &b.DateTo, &b.Status,
-		&b.CreatedAt, &b.UpdatedAt,
-	)
+	err := scanBooking(r.db.QueryRow(q, id), b)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, ErrBookingNotFound
@@ -85,77 +152,263 @@ func (r *BookingRepository) GetByID(id int) (*domain.Booking, error) {
 	return b, nil
 }
 
-func (r *BookingRepository) ListByTenant(tenantID int) ([]domain.Booking, error) {
-	const q = `
-		SELECT id, space_id, tenant_id, date_from, date_to, status, created_at, updated_at
-		FROM bookings
-		WHERE tenant_id = $1
-		ORDER BY date_from DESC, id DESC`
+// bookingsSort — единственная сортировка списков броней: сначала поздние
+const bookingsSort = "date_from"
+
+func (r *BookingRepository) ListByTenant(tenantID int, page PageRequest) (*domain.Page[domain.Booking], error) {
+	return r.listPage(`FROM bookings b WHERE b.tenant_id = $1`, tenantID, page)
+}
+
+func (r *BookingRepository) ListByOwner(ownerID int, page PageRequest) (*domain.Page[domain.Booking], error) {
+	return r.listPage(`FROM bookings b JOIN spaces s ON s.id = b.space_id WHERE s.owner_id = $1`, ownerID, page)
+}
+
+// listPage выбирает страницу броней по условию from с единственным параметром $1
+func (r *BookingRepository) listPage(from string, userID int, page PageRequest) (*domain.Page[domain.Booking], error) {
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) `+from, userID).Scan(&total); err != nil {
+		return nil, err
+	}
 
-	rows, err := r.db.Query(q, tenantID)
+	c, err := decodeCursor(page.Cursor, bookingsSort)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	var res []domain.Booking
-	for rows.Next() {
-		var b domain.Booking
-		if err := rows.Scan(
-			&b.ID, &b.SpaceID, &b.TenantID,
-			&b.DateFrom, &b.DateTo, &b.Status,
-			&b.CreatedAt, &b.UpdatedAt,
-		); err != nil {
-			return nil, err
+	limit := page.limit()
+	q := `SELECT ` + qualifyColumns(bookingColumns, "b") + ` ` + from
+	args := []any{userID}
+	if c != nil {
+		q += " AND " + keysetCond("b.date_from", "b.id", true, 2)
+		args = append(args, c.Value, c.ID)
+	}
+	q += orderBy("b.date_from", "b.id", true) + fmt.Sprintf(" LIMIT $%d", len(args)+1)
+	args = append(args, limit+1)
+
+	rows, err := r.db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	items, err := collectBookings(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	items, next := nextCursor(items, limit, bookingsSort, func(b domain.Booking) (string, int) {
+		return b.DateFrom.Format(time.RFC3339Nano), b.ID
+	})
+	return &domain.Page[domain.Booking]{Items: items, NextCursor: next, Total: total}, nil
+}
+
+// UpdateStatus меняет статус брони, если текущий статус входит в from
+func (r *BookingRepository) UpdateStatus(id int, from []domain.BookingStatus, status domain.BookingStatus, changedBy int, actor domain.HistoryActor, reason *string) error {
+	// Начинаем транзакцию
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
 		}
-		res = append(res, b)
+	}()
+
+	if err = updateStatusTx(tx, id, from, status, &changedBy, actor, reason); err != nil {
+		return err
 	}
-	return res, rows.Err()
+
+	err = tx.Commit()
+	return mapBookingError(err)
+}
+
+// Cancel отменяет бронь и в той же транзакции сохраняет рассчитанный возврат.
+// Отмена владельцем увеличивает счётчик owner_cancellations пространства.
+func (r *BookingRepository) Cancel(id int, from []domain.BookingStatus, changedBy *int, actor domain.HistoryActor, reason *string, refund *domain.RefundQuote) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	history := &domain.BookingStatusHistory{
+		NewStatus:    domain.BookingStatusCancelled,
+		ChangedBy:    changedBy,
+		Actor:        actor,
+		Reason:       reason,
+		RefundPct:    &refund.RefundPct,
+		RefundAmount: refund.RefundAmount,
+	}
+	if err = changeStatusTx(tx, id, from, history); err != nil {
+		return err
+	}
+
+	const refundQuery = `UPDATE bookings SET refund_pct = $1, refund_amount = $2 WHERE id = $3`
+	if _, err = tx.Exec(refundQuery, refund.RefundPct, refund.RefundAmount, id); err != nil {
+		return err
+	}
+
+	if actor == domain.HistoryActorOwner {
+		const counterQuery = `
+			UPDATE spaces
+			SET owner_cancellations = owner_cancellations + 1
+			WHERE id = (SELECT space_id FROM bookings WHERE id = $1)`
+		if _, err = tx.Exec(counterQuery, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
-func (r *BookingRepository) ListByOwner(ownerID int) ([]domain.Booking, error) {
-	const q = `
-		SELECT b.id, b.space_id, b.tenant_id, b.date_from, b.date_to,
-			   b.status, b.created_at, b.updated_at
+// Approve одобряет бронь и в той же транзакции отклоняет все ожидающие брони
+// этого пространства, пересекающиеся с ней по датам. Возвращает отклонённые брони.
+func (r *BookingRepository) Approve(id int, changedBy int, reason *string) ([]domain.Booking, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Одобрения в одном пространстве идут по очереди: без блокировки пространства две
+	// транзакции, отклоняющие заявки друг друга, упираются в блокировки друг друга.
+	// NO KEY UPDATE не конфликтует с KEY SHARE от внешнего ключа, поэтому новые заявки не ждут.
+	var (
+		spaceID  int
+		from, to time.Time
+	)
+	const lockQuery = `
+		SELECT s.id, b.date_from, b.date_to
 		FROM bookings b
 		JOIN spaces s ON s.id = b.space_id
-		WHERE s.owner_id = $1
-		ORDER BY b.date_from DESC, b.id DESC`
+		WHERE b.id = $1
+		FOR NO KEY UPDATE OF s`
+	err = tx.QueryRow(lockQuery, id).Scan(&spaceID, &from, &to)
+	if err == sql.ErrNoRows {
+		err = ErrBookingNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
 
-	rows, err := r.db.Query(q, ownerID)
+	// Проверка после блокировки видит брони, одобренные предыдущей транзакцией
+	overlap, err := hasApprovedOverlap(tx, spaceID, from, to, &id)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	if overlap {
+		err = ErrOverlappingBooking
+		return nil, err
+	}
 
-	var res []domain.Booking
-	for rows.Next() {
-		var b domain.Booking
-		if err := rows.Scan(
-			&b.ID, &b.SpaceID, &b.TenantID,
-			&b.DateFrom, &b.DateTo, &b.Status,
-			&b.CreatedAt, &b.UpdatedAt,
-		); err != nil {
+	if err = updateStatusTx(tx, id, []domain.BookingStatus{domain.BookingStatusPending}, domain.BookingStatusApproved, &changedBy, domain.HistoryActorOwner, reason); err != nil {
+		return nil, err
+	}
+
+	superseded, err := rejectCompetingTx(tx, id, changedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		err = mapBookingError(err)
+		return nil, err
+	}
+	return superseded, nil
+}
+
+// rejectCompetingTx отклоняет ожидающие заявки, пересекающиеся с одобренной бронью id
+// (в её текущих датах внутри транзакции). Возвращает отклонённые брони.
+func rejectCompetingTx(tx *sql.Tx, id, changedBy int) ([]domain.Booking, error) {
+	// Конкурирующие заявки блокируем, чтобы их не отменили параллельно
+	competingQuery := `
+		SELECT ` + qualifyColumns(bookingColumns, "c") + `
+		FROM bookings c
+		JOIN bookings a ON a.id = $1
+		WHERE c.space_id = a.space_id
+		  AND c.id <> a.id
+		  AND c.status = 'pending'
+		  AND NOT (c.date_to <= a.date_from OR c.date_from >= a.date_to)
+		ORDER BY c.id
+		FOR UPDATE OF c`
+
+	rows, err := tx.Query(competingQuery, id)
+	if err != nil {
+		return nil, err
+	}
+	superseded, err := collectBookings(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	supersededReason := fmt.Sprintf("superseded by booking #%d", id)
+	for i := range superseded {
+		if err := updateStatusTx(tx, superseded[i].ID, []domain.BookingStatus{domain.BookingStatusPending}, domain.BookingStatusRejected, &changedBy, domain.HistoryActorOwner, &supersededReason); err != nil {
 			return nil, err
 		}
-		res = append(res, b)
+		superseded[i].Status = domain.BookingStatusRejected
 	}
-	return res, rows.Err()
+
+	return superseded, nil
 }
 
-func (r *BookingRepository) UpdateStatus(id int, status domain.BookingStatus, changedBy int, reason *string) error {
-	// Получаем текущий статус
-	var oldStatus domain.BookingStatus
-	const getStatusQuery = `SELECT status FROM bookings WHERE id = $1`
-	err := r.db.QueryRow(getStatusQuery, id).Scan(&oldStatus)
+// ExpirePending переводит в expired ожидающие брони, созданные раньше createdBefore,
+// а также те, чья дата заезда уже наступила. Смена статуса записывается от имени системы.
+func (r *BookingRepository) ExpirePending(createdBefore time.Time, reason string) ([]domain.Booking, error) {
+	tx, err := r.db.Begin()
 	if err != nil {
-		return err
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// SKIP LOCKED: брони, которые прямо сейчас одобряют или отменяют, не трогаем
+	query := `
+		SELECT ` + bookingColumns + `
+		FROM bookings
+		WHERE status = 'pending'
+		  AND (created_at < $1 OR date_from <= NOW())
+		ORDER BY id
+		FOR UPDATE SKIP LOCKED`
+
+	rows, err := tx.Query(query, createdBefore)
+	if err != nil {
+		return nil, err
+	}
+	expired, err := collectBookings(rows)
+	if err != nil {
+		return nil, err
 	}
 
-	// Начинаем транзакцию
+	for i := range expired {
+		if err = updateStatusTx(tx, expired[i].ID, []domain.BookingStatus{domain.BookingStatusPending}, domain.BookingStatusExpired, nil, domain.HistoryActorSystem, &reason); err != nil {
+			return nil, err
+		}
+		expired[i].Status = domain.BookingStatusExpired
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return expired, nil
+}
+
+// CompleteFinished переводит в completed одобренные брони, период которых уже закончился.
+// Смена статуса записывается от имени системы.
+func (r *BookingRepository) CompleteFinished(reason string) ([]domain.Booking, error) {
 	tx, err := r.db.Begin()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer func() {
 		if err != nil {
@@ -163,61 +416,190 @@ func (r *BookingRepository) UpdateStatus(id int, status domain.BookingStatus, ch
 		}
 	}()
 
-	// Обновляем статус в основной таблице
-	const updateQuery = `
+	query := `
+		SELECT ` + bookingColumns + `
+		FROM bookings
+		WHERE status = 'approved'
+		  AND date_to <= NOW()
+		ORDER BY id
+		FOR UPDATE SKIP LOCKED`
+
+	rows, err := tx.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	completed, err := collectBookings(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range completed {
+		if err = updateStatusTx(tx, completed[i].ID, []domain.BookingStatus{domain.BookingStatusApproved}, domain.BookingStatusCompleted, nil, domain.HistoryActorSystem, &reason); err != nil {
+			return nil, err
+		}
+		completed[i].Status = domain.BookingStatusCompleted
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return completed, nil
+}
+
+// CheckIn отмечает заезд по одобренной брони. Повторная отметка не перезаписывает время.
+func (r *BookingRepository) CheckIn(id int) (at time.Time, err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	const query = `
 		UPDATE bookings
-		SET status = $1, updated_at = NOW()
-		WHERE id = $2
-	`
+		SET checked_in_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = 'approved' AND checked_in_at IS NULL
+		RETURNING checked_in_at, space_id, tenant_id`
+
+	evt := domain.BookingEvent{Type: domain.BookingEventCheckedIn, BookingID: id}
+	err = tx.QueryRow(query, id).Scan(&at, &evt.SpaceID, &evt.TenantID)
+	if err == sql.ErrNoRows {
+		err = ErrBookingNotCheckable
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
 
-	res, err := tx.Exec(updateQuery, status, id)
+	if err = enqueueEventTx(tx, evt); err != nil {
+		return time.Time{}, err
+	}
+	if err = tx.Commit(); err != nil {
+		return time.Time{}, err
+	}
+	return at, nil
+}
+
+// CheckOut отмечает выезд и завершает бронь в одной транзакции
+func (r *BookingRepository) CheckOut(id int, changedBy int) error {
+	tx, err := r.db.Begin()
 	if err != nil {
 		return err
 	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
 
-	n, err := res.RowsAffected()
-	if err != nil {
+	if err = updateStatusTx(tx, id, []domain.BookingStatus{domain.BookingStatusApproved}, domain.BookingStatusCompleted, &changedBy, domain.HistoryActorOwner, nil); err != nil {
 		return err
 	}
 
-	if n == 0 {
-		return ErrBookingNotFound
+	const query = `UPDATE bookings SET checked_out_at = NOW() WHERE id = $1`
+	if _, err = tx.Exec(query, id); err != nil {
+		return err
 	}
 
-	// Записываем в историю через транзакцию
-	history := &domain.BookingStatusHistory{
-		BookingID: id,
-		OldStatus: &oldStatus,
+	return tx.Commit()
+}
+
+// updateStatusTx меняет статус брони, пишет запись в историю и ставит событие
+// в outbox внутри переданной транзакции. Переход разрешён только из статусов from,
+// иначе ErrInvalidStatusTransition. changedBy равен nil, когда статус меняет система.
+func updateStatusTx(tx *sql.Tx, id int, from []domain.BookingStatus, status domain.BookingStatus, changedBy *int, actor domain.HistoryActor, reason *string) error {
+	return changeStatusTx(tx, id, from, &domain.BookingStatusHistory{
 		NewStatus: status,
 		ChangedBy: changedBy,
-		ChangedAt: time.Now(),
+		Actor:     actor,
 		Reason:    reason,
+	})
+}
+
+// changeStatusTx переводит бронь в history.NewStatus и записывает history в историю,
+// дополнив её бронью, старым статусом и временем изменения
+func changeStatusTx(tx *sql.Tx, id int, from []domain.BookingStatus, history *domain.BookingStatusHistory) error {
+	status := history.NewStatus
+
+	// Получаем текущий статус и блокируем строку до конца транзакции,
+	// чтобы параллельные смены статуса не перетёрли друг друга
+	var (
+		oldStatus         domain.BookingStatus
+		spaceID, tenantID int
+	)
+	const getStatusQuery = `SELECT status, space_id, tenant_id FROM bookings WHERE id = $1 FOR UPDATE`
+	err := tx.QueryRow(getStatusQuery, id).Scan(&oldStatus, &spaceID, &tenantID)
+	if err == sql.ErrNoRows {
+		return ErrBookingNotFound
+	}
+	if err != nil {
+		return err
+	}
+	// Статус мог измениться между проверкой в сервисе и блокировкой строки
+	if !slices.Contains(from, oldStatus) {
+		return ErrInvalidStatusTransition
 	}
 
+	// Обновляем статус в основной таблице
+	const updateQuery = `
+		UPDATE bookings
+		SET status = $1, updated_at = NOW()
+		WHERE id = $2
+	`
+
+	if _, err := tx.Exec(updateQuery, status, id); err != nil {
+		return mapBookingError(err)
+	}
+
+	// Записываем в историю через транзакцию
+	history.BookingID = id
+	history.OldStatus = &oldStatus
+	history.ChangedAt = time.Now()
+
 	// Используем временный репозиторий для транзакции
-	tempHistoryRepo := NewBookingStatusHistoryRepository(tx)
-	if err := tempHistoryRepo.Create(history); err != nil {
+	if err := NewBookingStatusHistoryRepository(tx).Create(history); err != nil {
 		return err
 	}
 
-	return tx.Commit()
+	eventType, ok := statusEvents[status]
+	if !ok {
+		return nil
+	}
+	if status == domain.BookingStatusCancelled && history.Actor == domain.HistoryActorOwner {
+		eventType = domain.BookingEventCancelledByOwner
+	}
+	return enqueueEventTx(tx, domain.BookingEvent{
+		Type:      eventType,
+		BookingID: id,
+		SpaceID:   spaceID,
+		TenantID:  tenantID,
+	})
 }
 
+// overlapCond — условие пересечения брони с полуинтервалом [$2, $3).
+// Нет пересечения = (date_to <= from) OR (date_from >= to),
+// нам нужны ИМЕННО пересекающиеся, поэтому NOT (...)
+const overlapCond = `NOT (date_to <= $2 OR date_from >= $3)`
+
 func (r *BookingRepository) HasApprovedOverlap(
 	spaceID int,
 	from, to time.Time,
 	excludeID *int,
 ) (bool, error) {
+	return hasApprovedOverlap(r.db, spaceID, from, to, excludeID)
+}
+
+// hasApprovedOverlap работает и с *sql.DB, и внутри транзакции
+func hasApprovedOverlap(db sqlDB, spaceID int, from, to time.Time, excludeID *int) (bool, error) {
 	query := `
 		SELECT EXISTS (
 			SELECT 1
 			FROM bookings
 			WHERE space_id = $1
 			  AND status = 'approved'
-			  -- нет пересечения = (date_to <= from) OR (date_from >= to)
-			  -- нам нужны ИМЕННО пересекающиеся, поэтому NOT (...)
-			  AND NOT (date_to <= $2 OR date_from >= $3)
-	`
+			  AND ` + overlapCond
 	args := []any{spaceID, from, to}
 
 	if excludeID != nil {
@@ -228,12 +610,39 @@ func (r *BookingRepository) HasApprovedOverlap(
 	query += ")"
 
 	var exists bool
-	if err := r.db.QueryRow(query, args...).Scan(&exists); err != nil {
+	if err := db.QueryRow(query, args...).Scan(&exists); err != nil {
 		return false, err
 	}
 	return exists, nil
 }
 
+// ListOverlapping возвращает брони пространства с указанными статусами,
+// пересекающиеся с периодом [from, to)
+func (r *BookingRepository) ListOverlapping(
+	spaceID int,
+	from, to time.Time,
+	statuses []domain.BookingStatus,
+) ([]domain.Booking, error) {
+	query := `
+		SELECT ` + bookingColumns + `
+		FROM bookings
+		WHERE space_id = $1
+		  AND ` + overlapCond + `
+		  AND status = ANY($4)
+		ORDER BY date_from ASC, id ASC`
+
+	names := make([]string, len(statuses))
+	for i, st := range statuses {
+		names[i] = string(st)
+	}
+
+	rows, err := r.db.Query(query, spaceID, from, to, pq.Array(names))
+	if err != nil {
+		return nil, err
+	}
+	return collectBookings(rows)
+}
+
 // GetStatusHistory возвращает историю статусов бронирования
 func (r *BookingRepository) GetStatusHistory(bookingID int) ([]domain.BookingStatusHistory, error) {
 	return r.historyRepo.GetByBookingID(bookingID)
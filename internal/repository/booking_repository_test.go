@@ -0,0 +1,284 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"SpaceBookProject/internal/domain"
+)
+
+// openTestDB подключается к базе с применёнными миграциями из TEST_DATABASE_DSN
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.Ping(); err != nil {
+		t.Fatalf("ping db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// createTestUser создаёт пользователя; его пространства и брони удаляются каскадно после теста
+func createTestUser(t *testing.T, db *sql.DB, role domain.UserRole) int {
+	t.Helper()
+
+	const query = `
+		INSERT INTO users (email, password_hash, role, first_name, last_name)
+		VALUES ($1, 'x', $2, 'Test', 'User')
+		RETURNING id`
+
+	email := fmt.Sprintf("%s-%d@test.local", role, time.Now().UnixNano())
+	var id int
+	if err := db.QueryRow(query, email, role).Scan(&id); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	t.Cleanup(func() { db.Exec(`DELETE FROM users WHERE id = $1`, id) })
+	return id
+}
+
+func createTestSpace(t *testing.T, db *sql.DB, ownerID int) int {
+	t.Helper()
+
+	var id int
+	err := db.QueryRow(
+		`INSERT INTO spaces (owner_id, title, area_m2, price) VALUES ($1, 'Test space', 10, 1000) RETURNING id`,
+		ownerID,
+	).Scan(&id)
+	if err != nil {
+		t.Fatalf("create space: %v", err)
+	}
+	return id
+}
+
+func TestApproveConcurrentOverlapping(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewBookingRepository(db)
+
+	ownerID := createTestUser(t, db, domain.RoleOwner)
+	tenantID := createTestUser(t, db, domain.RoleTenant)
+	spaceID := createTestSpace(t, db, ownerID)
+
+	const n = 8
+	from := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 1, 0)
+	ids := make([]int, n)
+	for i := range ids {
+		// Все заявки пересекаются по дню from+i
+		b := &domain.Booking{
+			SpaceID:  spaceID,
+			TenantID: tenantID,
+			Status:   domain.BookingStatusPending,
+			DateFrom: from.AddDate(0, 0, i),
+			DateTo:   from.AddDate(0, 0, n+1),
+		}
+		if err := repo.Create(b); err != nil {
+			t.Fatalf("create booking: %v", err)
+		}
+		ids[i] = b.ID
+	}
+
+	errs := make([]error, n)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, errs[i] = repo.Approve(id, ownerID, nil)
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	approved := 0
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			approved++
+		case errors.Is(err, ErrOverlappingBooking):
+		default:
+			t.Errorf("approve booking %d: unexpected error %v", ids[i], err)
+		}
+	}
+	if approved != 1 {
+		t.Fatalf("approved %d bookings, want exactly 1", approved)
+	}
+
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM bookings WHERE space_id = $1 AND status = 'approved'`, spaceID).Scan(&count)
+	if err != nil {
+		t.Fatalf("count approved: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("%d approved bookings in db, want 1", count)
+	}
+}
+
+func TestApproveCancelledBooking(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewBookingRepository(db)
+
+	ownerID := createTestUser(t, db, domain.RoleOwner)
+	tenantID := createTestUser(t, db, domain.RoleTenant)
+	spaceID := createTestSpace(t, db, ownerID)
+
+	from := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 1, 0)
+	b := &domain.Booking{
+		SpaceID:  spaceID,
+		TenantID: tenantID,
+		Status:   domain.BookingStatusPending,
+		DateFrom: from,
+		DateTo:   from.AddDate(0, 0, 1),
+	}
+	if err := repo.Create(b); err != nil {
+		t.Fatalf("create booking: %v", err)
+	}
+
+	pending := []domain.BookingStatus{domain.BookingStatusPending}
+	if err := repo.Cancel(b.ID, pending, &tenantID, domain.HistoryActorTenant, nil, &domain.RefundQuote{}); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+
+	if _, err := repo.Approve(b.ID, ownerID, nil); !errors.Is(err, ErrInvalidStatusTransition) {
+		t.Fatalf("approve cancelled booking: got %v, want ErrInvalidStatusTransition", err)
+	}
+
+	var events int
+	err := db.QueryRow(
+		`SELECT COUNT(*) FROM booking_outbox WHERE (payload->>'booking_id')::int = $1 AND event_type = 'approved'`,
+		b.ID,
+	).Scan(&events)
+	if err != nil {
+		t.Fatalf("count events: %v", err)
+	}
+	if events != 0 {
+		t.Fatalf("%d approved events in outbox, want 0", events)
+	}
+}
+
+func TestApplyChangeRequestRejectsCompeting(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewBookingRepository(db)
+
+	ownerID := createTestUser(t, db, domain.RoleOwner)
+	tenantID := createTestUser(t, db, domain.RoleTenant)
+	spaceID := createTestSpace(t, db, ownerID)
+
+	from := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 1, 0)
+	approved := &domain.Booking{SpaceID: spaceID, TenantID: tenantID, Status: domain.BookingStatusPending, DateFrom: from, DateTo: from.AddDate(0, 0, 2)}
+	pending := &domain.Booking{SpaceID: spaceID, TenantID: tenantID, Status: domain.BookingStatusPending, DateFrom: from.AddDate(0, 0, 10), DateTo: from.AddDate(0, 0, 12)}
+	for _, b := range []*domain.Booking{approved, pending} {
+		if err := repo.Create(b); err != nil {
+			t.Fatalf("create booking: %v", err)
+		}
+	}
+	if _, err := repo.Approve(approved.ID, ownerID, nil); err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+
+	cr := &domain.BookingChangeRequest{
+		BookingID:   approved.ID,
+		RequestedBy: tenantID,
+		OldDateFrom: approved.DateFrom,
+		OldDateTo:   approved.DateTo,
+		NewDateFrom: pending.DateFrom,
+		NewDateTo:   pending.DateTo,
+	}
+	if err := repo.CreateChangeRequest(cr); err != nil {
+		t.Fatalf("create change request: %v", err)
+	}
+	if _, err := repo.ApplyChangeRequest(cr.ID, ownerID, nil); err != nil {
+		t.Fatalf("apply change request: %v", err)
+	}
+
+	got, err := repo.GetByID(pending.ID)
+	if err != nil {
+		t.Fatalf("get booking: %v", err)
+	}
+	if got.Status != domain.BookingStatusRejected {
+		t.Fatalf("competing booking status %s, want rejected", got.Status)
+	}
+}
+
+func TestApplyChangeRequestPastDates(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewBookingRepository(db)
+
+	ownerID := createTestUser(t, db, domain.RoleOwner)
+	tenantID := createTestUser(t, db, domain.RoleTenant)
+	spaceID := createTestSpace(t, db, ownerID)
+
+	from := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 1, 0)
+	b := &domain.Booking{SpaceID: spaceID, TenantID: tenantID, Status: domain.BookingStatusPending, DateFrom: from, DateTo: from.AddDate(0, 0, 2)}
+	if err := repo.Create(b); err != nil {
+		t.Fatalf("create booking: %v", err)
+	}
+
+	// Заявка, пролежавшая до наступления новых дат
+	past := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	cr := &domain.BookingChangeRequest{
+		BookingID:   b.ID,
+		RequestedBy: tenantID,
+		OldDateFrom: b.DateFrom,
+		OldDateTo:   b.DateTo,
+		NewDateFrom: past,
+		NewDateTo:   past.AddDate(0, 0, 2),
+	}
+	if err := repo.CreateChangeRequest(cr); err != nil {
+		t.Fatalf("create change request: %v", err)
+	}
+	if _, err := repo.ApplyChangeRequest(cr.ID, ownerID, nil); !errors.Is(err, ErrChangeDatesPassed) {
+		t.Fatalf("apply stale change request: got %v, want ErrChangeDatesPassed", err)
+	}
+}
+
+// Арендатор отменяет бронь с возвратом, посчитанным для pending, а владелец успел её одобрить
+func TestCancelAfterConcurrentApproval(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewBookingRepository(db)
+
+	ownerID := createTestUser(t, db, domain.RoleOwner)
+	tenantID := createTestUser(t, db, domain.RoleTenant)
+	spaceID := createTestSpace(t, db, ownerID)
+
+	from := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 1, 0)
+	b := &domain.Booking{SpaceID: spaceID, TenantID: tenantID, Status: domain.BookingStatusPending, DateFrom: from, DateTo: from.AddDate(0, 0, 2)}
+	if err := repo.Create(b); err != nil {
+		t.Fatalf("create booking: %v", err)
+	}
+	read, err := repo.GetByID(b.ID)
+	if err != nil {
+		t.Fatalf("get booking: %v", err)
+	}
+
+	if _, err := repo.Approve(b.ID, ownerID, nil); err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+
+	refund := &domain.RefundQuote{BookingID: b.ID, RefundPct: 100}
+	err = repo.Cancel(b.ID, []domain.BookingStatus{read.Status}, &tenantID, domain.HistoryActorTenant, nil, refund)
+	if !errors.Is(err, ErrInvalidStatusTransition) {
+		t.Fatalf("cancel with stale status: got %v, want ErrInvalidStatusTransition", err)
+	}
+
+	got, err := repo.GetByID(b.ID)
+	if err != nil {
+		t.Fatalf("get booking: %v", err)
+	}
+	if got.Status != domain.BookingStatusApproved {
+		t.Fatalf("booking status %s, want approved", got.Status)
+	}
+}
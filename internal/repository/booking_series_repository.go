@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"SpaceBookProject/internal/domain"
+)
+
+var ErrBookingSeriesNotFound = errors.New("booking series not found")
+
+// CreateSeries создаёт серию и все её брони одной транзакцией
+func (r *BookingRepository) CreateSeries(series *domain.BookingSeries, bookings []*domain.Booking) error {
+	const query = `
+		INSERT INTO booking_series (space_id, tenant_id, freq, "interval", until, count)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	err = tx.QueryRow(
+		query,
+		series.SpaceID,
+		series.TenantID,
+		series.Freq,
+		series.Interval,
+		series.Until,
+		series.Count,
+	).Scan(&series.ID, &series.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	for _, b := range bookings {
+		b.SeriesID = &series.ID
+		if err = insertBookingTx(tx, b); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *BookingRepository) GetSeries(id int) (*domain.BookingSeries, error) {
+	const query = `
+		SELECT id, space_id, tenant_id, freq, "interval", until, count, created_at
+		FROM booking_series
+		WHERE id = $1`
+
+	s := &domain.BookingSeries{}
+	err := r.db.QueryRow(query, id).Scan(
+		&s.ID,
+		&s.SpaceID,
+		&s.TenantID,
+		&s.Freq,
+		&s.Interval,
+		&s.Until,
+		&s.Count,
+		&s.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrBookingSeriesNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// ListBySeries возвращает все повторения серии в хронологическом порядке
+func (r *BookingRepository) ListBySeries(seriesID int) ([]domain.Booking, error) {
+	q := `
+		SELECT ` + bookingColumns + `
+		FROM bookings
+		WHERE series_id = $1
+		ORDER BY date_from ASC, id ASC`
+
+	rows, err := r.db.Query(q, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	return collectBookings(rows)
+}
@@ -26,8 +26,9 @@ func NewBookingStatusHistoryRepository(db sqlDB) *BookingStatusHistoryRepository
 func (r *BookingStatusHistoryRepository) Create(history *domain.BookingStatusHistory) error {
 	const query = `
 		INSERT INTO booking_status_history 
-		(booking_id, old_status, new_status, changed_by, reason, changed_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		(booking_id, old_status, new_status, changed_by, actor, reason, changed_at,
+		 old_date_from, old_date_to, new_date_from, new_date_to, refund_pct, refund_amount)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 		RETURNING id, created_at;
 	`
 
@@ -37,8 +38,15 @@ func (r *BookingStatusHistoryRepository) Create(history *domain.BookingStatusHis
 		history.OldStatus,
 		history.NewStatus,
 		history.ChangedBy,
+		history.Actor,
 		history.Reason,
 		history.ChangedAt,
+		history.OldDateFrom,
+		history.OldDateTo,
+		history.NewDateFrom,
+		history.NewDateTo,
+		history.RefundPct,
+		history.RefundAmount,
 	).Scan(&history.ID, &history.CreatedAt)
 }
 
@@ -47,7 +55,9 @@ func (r *BookingStatusHistoryRepository) GetByBookingID(bookingID int) ([]domain
 	const query = `
 		SELECT 
 			id, booking_id, old_status, new_status, 
-			changed_by, changed_at, reason, created_at
+			changed_by, actor, changed_at, reason,
+			old_date_from, old_date_to, new_date_from, new_date_to,
+			refund_pct, refund_amount, created_at
 		FROM booking_status_history
 		WHERE booking_id = $1
 		ORDER BY changed_at DESC, id DESC
@@ -71,8 +81,15 @@ func (r *BookingStatusHistoryRepository) GetByBookingID(bookingID int) ([]domain
 			&oldStatus,
 			&h.NewStatus,
 			&h.ChangedBy,
+			&h.Actor,
 			&h.ChangedAt,
 			&reason,
+			&h.OldDateFrom,
+			&h.OldDateTo,
+			&h.NewDateFrom,
+			&h.NewDateTo,
+			&h.RefundPct,
+			&h.RefundAmount,
 			&h.CreatedAt,
 		)
 		if err != nil {
@@ -105,7 +122,8 @@ func (r *BookingStatusHistoryRepository) RecordInitialStatus(
 		BookingID: bookingID,
 		OldStatus: nil, // Нет старого статуса при создании
 		NewStatus: status,
-		ChangedBy: userID,
+		ChangedBy: &userID,
+		Actor:     domain.HistoryActorTenant,
 		ChangedAt: time.Now(),
 		Reason:    nil,
 	}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"SpaceBookProject/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrCollectionNotFound = errors.New("collection not found")
+	ErrCollectionExists   = errors.New("collection with this name already exists")
+)
+
+const collectionColumns = `c.id, c.user_id, c.name, c.share_token, c.created_at, c.updated_at,
+	(SELECT COUNT(*) FROM collection_spaces cs WHERE cs.collection_id = c.id)`
+
+func scanCollection(row rowScanner, c *domain.Collection) error {
+	return row.Scan(&c.ID, &c.UserID, &c.Name, &c.ShareToken, &c.CreatedAt, &c.UpdatedAt, &c.SpaceCount)
+}
+
+// mapFavoritesError переводит нарушения ограничений в ошибки репозитория
+func mapFavoritesError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "23505":
+			return ErrCollectionExists
+		case "23503":
+			return ErrSpaceNotFound
+		}
+	}
+	return err
+}
+
+func (r *FavoritesRepository) CreateCollection(ctx context.Context, c *domain.Collection) error {
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO collections (user_id, name)
+		VALUES ($1, $2)
+		RETURNING id, created_at, updated_at
+	`, c.UserID, c.Name).Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
+	return mapFavoritesError(err)
+}
+
+func (r *FavoritesRepository) GetCollection(ctx context.Context, id int) (*domain.Collection, error) {
+	return r.getCollection(ctx, `c.id = $1`, id)
+}
+
+func (r *FavoritesRepository) GetCollectionByToken(ctx context.Context, token string) (*domain.Collection, error) {
+	return r.getCollection(ctx, `c.share_token = $1`, token)
+}
+
+func (r *FavoritesRepository) getCollection(ctx context.Context, cond string, arg any) (*domain.Collection, error) {
+	c := &domain.Collection{}
+	err := scanCollection(r.db.QueryRowContext(ctx, `SELECT `+collectionColumns+` FROM collections c WHERE `+cond, arg), c)
+	if err == sql.ErrNoRows {
+		return nil, ErrCollectionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (r *FavoritesRepository) ListCollections(ctx context.Context, userID int) ([]domain.Collection, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+collectionColumns+`
+		FROM collections c
+		WHERE c.user_id = $1
+		ORDER BY c.created_at DESC, c.id DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []domain.Collection{}
+	for rows.Next() {
+		var c domain.Collection
+		if err := scanCollection(rows, &c); err != nil {
+			return nil, err
+		}
+		result = append(result, c)
+	}
+	return result, rows.Err()
+}
+
+func (r *FavoritesRepository) RenameCollection(ctx context.Context, id int, name string) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE collections SET name = $1, updated_at = NOW() WHERE id = $2
+	`, name, id)
+	if err != nil {
+		return mapFavoritesError(err)
+	}
+	return collectionAffected(res)
+}
+
+// SetShareToken открывает подборку по ссылке или, при nil, закрывает её
+func (r *FavoritesRepository) SetShareToken(ctx context.Context, id int, token *string) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE collections SET share_token = $1, updated_at = NOW() WHERE id = $2
+	`, token, id)
+	if err != nil {
+		return err
+	}
+	return collectionAffected(res)
+}
+
+func (r *FavoritesRepository) DeleteCollection(ctx context.Context, id int) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM collections WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	return collectionAffected(res)
+}
+
+func collectionAffected(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrCollectionNotFound
+	}
+	return nil
+}
+
+// AddToCollection добавляет пространства; уже добавленные пропускаются
+func (r *FavoritesRepository) AddToCollection(ctx context.Context, id int, spaceIDs []int) error {
+	ids := make(pq.Int64Array, len(spaceIDs))
+	for i, v := range spaceIDs {
+		ids[i] = int64(v)
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO collection_spaces (collection_id, space_id)
+		SELECT $1, unnest($2::INTEGER[])
+		ON CONFLICT (collection_id, space_id) DO NOTHING
+	`, id, ids)
+	return mapFavoritesError(err)
+}
+
+func (r *FavoritesRepository) RemoveFromCollection(ctx context.Context, id, spaceID int) error {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM collection_spaces
+		WHERE collection_id = $1 AND space_id = $2
+	`, id, spaceID)
+	return err
+}
+
+// ListCollectionSpaces возвращает пространства подборки, последние добавленные сверху.
+// onlyActive скрывает архивные пространства — для просмотра по ссылке.
+func (r *FavoritesRepository) ListCollectionSpaces(ctx context.Context, id int, onlyActive bool) ([]domain.Space, error) {
+	query := `
+		SELECT ` + qualifyColumns(spaceColumns, "s") + `
+		FROM spaces s
+		JOIN collection_spaces cs ON cs.space_id = s.id
+		WHERE cs.collection_id = $1`
+	if onlyActive {
+		query += ` AND s.is_active = TRUE`
+	}
+	query += ` ORDER BY cs.added_at DESC, s.id DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []domain.Space{}
+	for rows.Next() {
+		var s domain.Space
+		if err := scanSpace(rows, &s); err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+	return result, rows.Err()
+}
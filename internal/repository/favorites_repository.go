@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"time"
 
 	"SpaceBookProject/internal/domain"
 )
@@ -17,11 +19,11 @@ func NewFavoritesRepository(db *sql.DB) *FavoritesRepository {
 
 func (r *FavoritesRepository) Add(ctx context.Context, userID, spaceID int) error {
 	_, err := r.db.ExecContext(ctx, `
-		INSERT INTO favorites (user_id, space_id)
-		VALUES ($1, $2)
+		INSERT INTO favorites (user_id, space_id, notified_price)
+		VALUES ($1, $2, (SELECT price FROM spaces WHERE id = $2))
 		ON CONFLICT (user_id, space_id) DO NOTHING
 	`, userID, spaceID)
-	return err
+	return mapFavoritesError(err)
 }
 
 func (r *FavoritesRepository) Remove(ctx context.Context, userID, spaceID int) error {
@@ -32,36 +34,62 @@ func (r *FavoritesRepository) Remove(ctx context.Context, userID, spaceID int) e
 	return err
 }
 
-func (r *FavoritesRepository) List(ctx context.Context, userID int) ([]domain.Space, error) {
-	rows, err := r.db.QueryContext(ctx, `
-		SELECT s.id, s.owner_id, s.title, s.description, s.area_m2, s.price, s.phone, s.created_at, s.updated_at
+// favoritesSort — избранное отдаётся в порядке добавления, новые сверху
+const favoritesSort = "added_at"
+
+func (r *FavoritesRepository) List(ctx context.Context, userID int, page PageRequest) (*domain.Page[domain.Space], error) {
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM favorites WHERE user_id = $1`, userID).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	c, err := decodeCursor(page.Cursor, favoritesSort)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := page.limit()
+	q := `
+		SELECT ` + qualifyColumns(spaceColumns, "s") + `, f.created_at
 		FROM spaces s
 		JOIN favorites f ON f.space_id = s.id
-		WHERE f.user_id = $1
-		ORDER BY f.created_at DESC, s.id DESC
-	`, userID)
+		WHERE f.user_id = $1`
+	args := []any{userID}
+	if c != nil {
+		q += " AND " + keysetCond("f.created_at", "s.id", true, 2)
+		args = append(args, c.Value, c.ID)
+	}
+	q += orderBy("f.created_at", "s.id", true) + fmt.Sprintf(" LIMIT $%d", len(args)+1)
+	args = append(args, limit+1)
+
+	rows, err := r.db.QueryContext(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var result []domain.Space
+	type favorite struct {
+		space   domain.Space
+		addedAt time.Time
+	}
+	var found []favorite
 	for rows.Next() {
-		var s domain.Space
-		if err := rows.Scan(
-			&s.ID,
-			&s.OwnerID,
-			&s.Title,
-			&s.Description,
-			&s.AreaM2,
-			&s.Price,
-			&s.Phone,
-			&s.CreatedAt,
-			&s.UpdatedAt,
-		); err != nil {
+		var f favorite
+		if err := scanSpace(extraScanner{rows, []any{&f.addedAt}}, &f.space); err != nil {
 			return nil, err
 		}
-		result = append(result, s)
+		found = append(found, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	found, next := nextCursor(found, limit, favoritesSort, func(f favorite) (string, int) {
+		return f.addedAt.Format(time.RFC3339Nano), f.space.ID
+	})
+	items := make([]domain.Space, len(found))
+	for i, f := range found {
+		items[i] = f.space
 	}
-	return result, rows.Err()
+	return &domain.Page[domain.Space]{Items: items, NextCursor: next, Total: total}, nil
 }
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"SpaceBookProject/internal/domain"
+)
+
+var ErrConversationNotFound = errors.New("conversation not found")
+
+const conversationColumns = `id, space_id, booking_id, tenant_id, owner_id, last_message_at, created_at`
+
+type MessageRepository struct {
+	db *sql.DB
+}
+
+func NewMessageRepository(db *sql.DB) *MessageRepository {
+	return &MessageRepository{db: db}
+}
+
+func scanConversation(row rowScanner, c *domain.Conversation) error {
+	return row.Scan(&c.ID, &c.SpaceID, &c.BookingID, &c.TenantID, &c.OwnerID, &c.LastMessageAt, &c.CreatedAt)
+}
+
+// BookingConversation возвращает переписку по брони, создавая её при первом обращении
+func (r *MessageRepository) BookingConversation(b *domain.Booking, ownerID int) (*domain.Conversation, error) {
+	const insertQuery = `
+		INSERT INTO conversations (space_id, booking_id, tenant_id, owner_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (booking_id) DO NOTHING`
+
+	if _, err := r.db.Exec(insertQuery, b.SpaceID, b.ID, b.TenantID, ownerID); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + conversationColumns + ` FROM conversations WHERE booking_id = $1`
+	c := &domain.Conversation{}
+	if err := scanConversation(r.db.QueryRow(query, b.ID), c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// InquiryConversation возвращает переписку-вопрос арендатора по пространству, создавая её при необходимости
+func (r *MessageRepository) InquiryConversation(spaceID, tenantID, ownerID int) (*domain.Conversation, error) {
+	const insertQuery = `
+		INSERT INTO conversations (space_id, tenant_id, owner_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (space_id, tenant_id) WHERE booking_id IS NULL DO NOTHING`
+
+	if _, err := r.db.Exec(insertQuery, spaceID, tenantID, ownerID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT ` + conversationColumns + `
+		FROM conversations
+		WHERE space_id = $1 AND tenant_id = $2 AND booking_id IS NULL`
+	c := &domain.Conversation{}
+	if err := scanConversation(r.db.QueryRow(query, spaceID, tenantID), c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (r *MessageRepository) GetConversation(id int) (*domain.Conversation, error) {
+	query := `SELECT ` + conversationColumns + ` FROM conversations WHERE id = $1`
+
+	c := &domain.Conversation{}
+	err := scanConversation(r.db.QueryRow(query, id), c)
+	if err == sql.ErrNoRows {
+		return nil, ErrConversationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ListConversations возвращает переписки пользователя с числом непрочитанных им сообщений,
+// сначала те, где сообщения были недавно
+func (r *MessageRepository) ListConversations(userID int) ([]domain.Conversation, error) {
+	query := `
+		SELECT ` + qualifyColumns(conversationColumns, "c") + `,
+		       (SELECT COUNT(*)
+		        FROM messages m
+		        WHERE m.conversation_id = c.id
+		          AND m.sender_id <> $1
+		          AND m.read_at IS NULL) AS unread
+		FROM conversations c
+		WHERE c.tenant_id = $1 OR c.owner_id = $1
+		ORDER BY COALESCE(c.last_message_at, c.created_at) DESC, c.id DESC`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.Conversation
+	for rows.Next() {
+		var c domain.Conversation
+		if err := rows.Scan(
+			&c.ID, &c.SpaceID, &c.BookingID, &c.TenantID, &c.OwnerID, &c.LastMessageAt, &c.CreatedAt,
+			&c.UnreadCount,
+		); err != nil {
+			return nil, err
+		}
+		res = append(res, c)
+	}
+	return res, rows.Err()
+}
+
+// AddMessage сохраняет сообщение, сдвигает время последней активности переписки
+// и ставит событие message_sent в outbox
+func (r *MessageRepository) AddMessage(m *domain.Message) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	const insertQuery = `
+		INSERT INTO messages (conversation_id, sender_id, body)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at`
+
+	if err = tx.QueryRow(insertQuery, m.ConversationID, m.SenderID, m.Body).Scan(&m.ID, &m.CreatedAt); err != nil {
+		return err
+	}
+
+	const touchQuery = `
+		UPDATE conversations SET last_message_at = $1 WHERE id = $2
+		RETURNING booking_id, space_id, tenant_id`
+
+	var bookingID sql.NullInt64
+	evt := domain.BookingEvent{Type: domain.BookingEventMessageSent, ConversationID: m.ConversationID, SenderID: m.SenderID}
+	if err = tx.QueryRow(touchQuery, m.CreatedAt, m.ConversationID).Scan(&bookingID, &evt.SpaceID, &evt.TenantID); err != nil {
+		return err
+	}
+	evt.BookingID = int(bookingID.Int64)
+
+	if err = enqueueEventTx(tx, evt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ReadMessages возвращает сообщения переписки и отмечает входящие для readerID прочитанными
+func (r *MessageRepository) ReadMessages(conversationID, readerID int) ([]domain.Message, error) {
+	const markQuery = `
+		UPDATE messages
+		SET read_at = NOW()
+		WHERE conversation_id = $1 AND sender_id <> $2 AND read_at IS NULL`
+
+	if _, err := r.db.Exec(markQuery, conversationID, readerID); err != nil {
+		return nil, err
+	}
+
+	const query = `
+		SELECT id, conversation_id, sender_id, body, read_at, created_at
+		FROM messages
+		WHERE conversation_id = $1
+		ORDER BY created_at ASC, id ASC`
+
+	rows, err := r.db.Query(query, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.Message
+	for rows.Next() {
+		var m domain.Message
+		if err := rows.Scan(&m.ID, &m.ConversationID, &m.SenderID, &m.Body, &m.ReadAt, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		res = append(res, m)
+	}
+	return res, rows.Err()
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"SpaceBookProject/internal/domain"
+)
+
+// Outbox-таблицы с одинаковой схемой: события по броням и уведомления пользователям
+const (
+	bookingOutbox      = "booking_outbox"
+	notificationOutbox = "notification_outbox"
+)
+
+// statusEvents — событие, которое порождает переход брони в статус
+var statusEvents = map[domain.BookingStatus]domain.BookingEventType{
+	domain.BookingStatusApproved:  domain.BookingEventApproved,
+	domain.BookingStatusRejected:  domain.BookingEventRejected,
+	domain.BookingStatusCancelled: domain.BookingEventCancelled,
+	domain.BookingStatusExpired:   domain.BookingEventExpired,
+	domain.BookingStatusCompleted: domain.BookingEventCompleted,
+	domain.BookingStatusNoShow:    domain.BookingEventNoShow,
+}
+
+// enqueueEventTx пишет событие в outbox внутри транзакции изменения брони:
+// событие сохраняется тогда и только тогда, когда фиксируется само изменение
+func enqueueEventTx(tx *sql.Tx, evt domain.BookingEvent) error {
+	if evt.At.IsZero() {
+		evt.At = time.Now()
+	}
+	return insertOutboxTx(tx, bookingOutbox, string(evt.Type), evt)
+}
+
+// enqueueBookingEventTx пишет событие по брони, дочитывая пространство и арендатора
+func enqueueBookingEventTx(tx *sql.Tx, eventType domain.BookingEventType, bookingID int) error {
+	evt := domain.BookingEvent{Type: eventType, BookingID: bookingID}
+	const query = `SELECT space_id, tenant_id FROM bookings WHERE id = $1`
+	if err := tx.QueryRow(query, bookingID).Scan(&evt.SpaceID, &evt.TenantID); err != nil {
+		return err
+	}
+	return enqueueEventTx(tx, evt)
+}
+
+// enqueueNotificationTx пишет уведомление в outbox в той же транзакции, где отмечается,
+// что пользователь уведомлён: отметка без уведомления не зафиксируется
+func enqueueNotificationTx(tx *sql.Tx, evt domain.NotificationEvent) error {
+	if evt.At.IsZero() {
+		evt.At = time.Now()
+	}
+	return insertOutboxTx(tx, notificationOutbox, string(evt.Type), evt)
+}
+
+func insertOutboxTx(tx *sql.Tx, table, eventType string, evt any) error {
+	payload, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO `+table+` (event_type, payload) VALUES ($1, $2)`, eventType, payload)
+	return err
+}
+
+// OutboxRepository читает одну outbox-таблицу; E — тип события в payload
+type OutboxRepository[E any] struct {
+	db    *sql.DB
+	table string
+}
+
+func NewOutboxRepository(db *sql.DB) *OutboxRepository[domain.BookingEvent] {
+	return &OutboxRepository[domain.BookingEvent]{db: db, table: bookingOutbox}
+}
+
+func NewNotificationOutboxRepository(db *sql.DB) *OutboxRepository[domain.NotificationEvent] {
+	return &OutboxRepository[domain.NotificationEvent]{db: db, table: notificationOutbox}
+}
+
+// Claim забирает до limit готовых к доставке событий. Строки выбираются с
+// FOR UPDATE SKIP LOCKED, поэтому параллельные диспетчеры получают разные события.
+// Взятое событие уходит из очереди на lease и сразу засчитывает попытку:
+// если процесс упадёт до отметки результата, событие доставится повторно.
+// Событие с нечитаемым payload сразу отмечается как failed и в выборку не попадает,
+// чтобы не блокировать доставку остальных.
+func (r *OutboxRepository[E]) Claim(limit int, lease time.Duration) ([]domain.OutboxEvent[E], error) {
+	query := `
+		UPDATE ` + r.table + ` o
+		SET attempts = o.attempts + 1,
+		    next_attempt_at = NOW() + make_interval(secs => $2)
+		FROM (
+			SELECT id
+			FROM ` + r.table + `
+			WHERE processed_at IS NULL AND failed_at IS NULL AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at, id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		) due
+		WHERE o.id = due.id
+		RETURNING o.id, o.payload, o.attempts`
+
+	rows, err := r.db.Query(query, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		res     []domain.OutboxEvent[E]
+		invalid = map[int64]error{}
+	)
+	for rows.Next() {
+		var (
+			e       domain.OutboxEvent[E]
+			payload []byte
+		)
+		if err := rows.Scan(&e.ID, &payload, &e.Attempts); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(payload, &e.Event); err != nil {
+			invalid[e.ID] = err
+			continue
+		}
+		res = append(res, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	// Ошибку отметки не возвращаем, чтобы не терять остальную выборку:
+	// строка вернётся в очередь после lease и будет отмечена при следующем Claim
+	for id, err := range invalid {
+		_ = r.Fail(id, "invalid payload: "+err.Error())
+	}
+
+	// RETURNING не сохраняет порядок подзапроса, а события доставляем по порядку записи
+	sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+	return res, nil
+}
+
+// MarkDone отмечает событие доставленным
+func (r *OutboxRepository[E]) MarkDone(id int64) error {
+	query := `UPDATE ` + r.table + ` SET processed_at = NOW(), last_error = NULL WHERE id = $1`
+	_, err := r.db.Exec(query, id)
+	return err
+}
+
+// Retry откладывает следующую попытку доставки на after
+func (r *OutboxRepository[E]) Retry(id int64, after time.Duration, lastErr string) error {
+	query := `
+		UPDATE ` + r.table + `
+		SET next_attempt_at = NOW() + make_interval(secs => $2), last_error = $3
+		WHERE id = $1`
+	_, err := r.db.Exec(query, id, after.Seconds(), lastErr)
+	return err
+}
+
+// Fail снимает событие с доставки после исчерпания попыток; строка остаётся для разбора
+func (r *OutboxRepository[E]) Fail(id int64, lastErr string) error {
+	query := `UPDATE ` + r.table + ` SET failed_at = NOW(), last_error = $2 WHERE id = $1`
+	_, err := r.db.Exec(query, id, lastErr)
+	return err
+}
+
+// DeleteProcessedBefore удаляет доставленные до before события. Возвращает число удалённых строк.
+func (r *OutboxRepository[E]) DeleteProcessedBefore(before time.Time) (int64, error) {
+	res, err := r.db.Exec(`DELETE FROM `+r.table+` WHERE processed_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClaimFailsInvalidPayload(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewNotificationOutboxRepository(db)
+
+	var badID, goodID int64
+	insert := `INSERT INTO notification_outbox (event_type, payload) VALUES ('price_drop', $1) RETURNING id`
+	if err := db.QueryRow(insert, `{"user_id": "not a number"}`).Scan(&badID); err != nil {
+		t.Fatalf("insert bad event: %v", err)
+	}
+	if err := db.QueryRow(insert, `{"type": "price_drop", "user_id": 1, "space_id": 1}`).Scan(&goodID); err != nil {
+		t.Fatalf("insert good event: %v", err)
+	}
+	t.Cleanup(func() { db.Exec(`DELETE FROM notification_outbox WHERE id IN ($1, $2)`, badID, goodID) })
+
+	events, err := repo.Claim(1000, time.Minute)
+	if err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+	var claimedGood bool
+	for _, e := range events {
+		switch e.ID {
+		case badID:
+			t.Errorf("event %d with invalid payload was claimed", badID)
+		case goodID:
+			claimedGood = true
+		}
+	}
+	if !claimedGood {
+		t.Errorf("event %d was not claimed", goodID)
+	}
+
+	var failed bool
+	if err := db.QueryRow(`SELECT failed_at IS NOT NULL FROM notification_outbox WHERE id = $1`, badID).Scan(&failed); err != nil {
+		t.Fatalf("read bad event: %v", err)
+	}
+	if !failed {
+		t.Fatalf("event %d with invalid payload is not marked failed", badID)
+	}
+}
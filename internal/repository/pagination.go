@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// PageRequest — параметры keyset-пагинации; пустой Cursor означает первую страницу
+type PageRequest struct {
+	Limit  int
+	Cursor string
+}
+
+func (p PageRequest) limit() int {
+	if p.Limit <= 0 {
+		return DefaultPageLimit
+	}
+	if p.Limit > MaxPageLimit {
+		return MaxPageLimit
+	}
+	return p.Limit
+}
+
+// cursor запоминает значение ключа сортировки и id последнего элемента страницы.
+// Значение хранится строкой: Postgres сам приводит параметр к типу колонки.
+type cursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    int    `json:"id"`
+}
+
+func (c cursor) encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor разбирает курсор и проверяет, что он выдан для той же сортировки
+func decodeCursor(s, sort string) (*cursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c cursor
+	if err := json.Unmarshal(b, &c); err != nil || c.Sort != sort {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// keysetCond — условие «после курсора» для сортировки (expr, idExpr) в направлении desc
+func keysetCond(expr, idExpr string, desc bool, i int) string {
+	op := ">"
+	if desc {
+		op = "<"
+	}
+	return fmt.Sprintf("(%s, %s) %s ($%d, $%d)", expr, idExpr, op, i, i+1)
+}
+
+// orderBy — ORDER BY для keyset-сортировки, id разрешает равенство ключей
+func orderBy(expr, idExpr string, desc bool) string {
+	dir := "ASC"
+	if desc {
+		dir = "DESC"
+	}
+	return fmt.Sprintf(" ORDER BY %s %s, %s %s", expr, dir, idExpr, dir)
+}
+
+// nextCursor обрезает лишний (limit+1)-й элемент и возвращает курсор следующей страницы
+func nextCursor[T any](items []T, limit int, sort string, key func(T) (string, int)) ([]T, *string) {
+	if items == nil {
+		items = []T{}
+	}
+	if len(items) <= limit {
+		return items, nil
+	}
+	items = items[:limit]
+	value, id := key(items[limit-1])
+	next := cursor{Sort: sort, Value: value, ID: id}.encode()
+	return items, &next
+}
+
+// extraScanner дочитывает вычисляемые колонки после колонок сущности
+type extraScanner struct {
+	row   rowScanner
+	extra []any
+}
+
+func (e extraScanner) Scan(dest ...any) error {
+	return e.row.Scan(append(dest, e.extra...)...)
+}
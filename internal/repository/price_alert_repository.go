@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"SpaceBookProject/internal/domain"
+)
+
+// GetPriceAlertSettings возвращает настройки пользователя или значения по умолчанию
+func (r *FavoritesRepository) GetPriceAlertSettings(ctx context.Context, userID int) (*domain.PriceAlertSettings, error) {
+	s := &domain.PriceAlertSettings{Enabled: true, ThresholdPct: domain.DefaultPriceDropThresholdPct}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT enabled, threshold_pct
+		FROM price_alert_settings
+		WHERE user_id = $1
+	`, userID).Scan(&s.Enabled, &s.ThresholdPct)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (r *FavoritesRepository) SavePriceAlertSettings(ctx context.Context, userID int, s *domain.PriceAlertSettings) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO price_alert_settings (user_id, enabled, threshold_pct)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET enabled = EXCLUDED.enabled, threshold_pct = EXCLUDED.threshold_pct, updated_at = NOW()
+	`, userID, s.Enabled, s.ThresholdPct)
+	return err
+}
+
+// ClaimPriceDrops находит пользователей, для которых новая цена ниже запомненной
+// больше чем на их порог, запоминает новую цену и в той же транзакции ставит
+// уведомления price_drop в outbox: цена не запомнится без уведомления.
+// Запомненная цена — максимум с последнего уведомления: рост цены поднимает её,
+// поэтому 100 → 200 → 150 сообщается как снижение с 200.
+func (r *FavoritesRepository) ClaimPriceDrops(ctx context.Context, spaceID, newPrice int) (drops []domain.PriceDrop, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	rows, err := tx.QueryContext(ctx, `
+		UPDATE favorites f
+		SET notified_price = $2
+		FROM (
+			SELECT fav.user_id, fav.notified_price AS old_price
+			FROM favorites fav
+			LEFT JOIN price_alert_settings p ON p.user_id = fav.user_id
+			WHERE fav.space_id = $1
+			  AND COALESCE(p.enabled, TRUE)
+			  AND fav.notified_price > $2
+			  AND (fav.notified_price - $2) * 100 > COALESCE(p.threshold_pct, $3) * fav.notified_price
+			FOR UPDATE OF fav
+		) d
+		WHERE f.space_id = $1 AND f.user_id = d.user_id
+		RETURNING d.user_id, d.old_price
+	`, spaceID, newPrice, domain.DefaultPriceDropThresholdPct)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var d domain.PriceDrop
+		if err = rows.Scan(&d.UserID, &d.OldPrice); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		drops = append(drops, d)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, d := range drops {
+		err = enqueueNotificationTx(tx, domain.NotificationEvent{
+			Type:     domain.NotificationPriceDrop,
+			UserID:   d.UserID,
+			SpaceID:  spaceID,
+			OldPrice: d.OldPrice,
+			NewPrice: newPrice,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE favorites SET notified_price = $2
+		WHERE space_id = $1 AND notified_price < $2
+	`, spaceID, newPrice)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return drops, nil
+}
+
+// ResetPriceBaseline запоминает текущую цену без уведомлений — например, после смены валюты
+func (r *FavoritesRepository) ResetPriceBaseline(ctx context.Context, spaceID, price int) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE favorites SET notified_price = $2 WHERE space_id = $1
+	`, spaceID, price)
+	return err
+}
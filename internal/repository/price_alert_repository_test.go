@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"SpaceBookProject/internal/domain"
+)
+
+// После роста цены снижение считается от максимума, а не от цены на момент добавления
+func TestClaimPriceDropsAfterIncrease(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewFavoritesRepository(db)
+	ctx := context.Background()
+
+	ownerID := createTestUser(t, db, domain.RoleOwner)
+	userID := createTestUser(t, db, domain.RoleTenant)
+	spaceID := createTestSpace(t, db, ownerID)
+	if err := repo.Add(ctx, userID, spaceID); err != nil {
+		t.Fatalf("add favorite: %v", err)
+	}
+
+	steps := []struct {
+		price   int
+		wantOld int // 0 — уведомления нет
+	}{
+		{price: 2000},
+		{price: 1500, wantOld: 2000},
+		{price: 1450},
+		{price: 1000, wantOld: 1500},
+	}
+	for _, step := range steps {
+		drops, err := repo.ClaimPriceDrops(ctx, spaceID, step.price)
+		if err != nil {
+			t.Fatalf("claim at %d: %v", step.price, err)
+		}
+		switch {
+		case step.wantOld == 0 && len(drops) != 0:
+			t.Errorf("price %d: got %+v, want no drops", step.price, drops)
+		case step.wantOld != 0 && (len(drops) != 1 || drops[0].UserID != userID || drops[0].OldPrice != step.wantOld):
+			t.Errorf("price %d: got %+v, want one drop from %d", step.price, drops, step.wantOld)
+		}
+	}
+
+	var queued int
+	err := db.QueryRow(
+		`SELECT COUNT(*) FROM notification_outbox WHERE event_type = 'price_drop' AND (payload->>'user_id')::int = $1`,
+		userID,
+	).Scan(&queued)
+	if err != nil {
+		t.Fatalf("count notifications: %v", err)
+	}
+	if queued != 2 {
+		t.Fatalf("%d price_drop notifications in outbox, want 2", queued)
+	}
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"SpaceBookProject/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+var ErrPricingRuleNotFound = errors.New("pricing rule not found")
+
+type PricingRuleRepository struct {
+	db *sql.DB
+}
+
+func NewPricingRuleRepository(db *sql.DB) *PricingRuleRepository {
+	return &PricingRuleRepository{db: db}
+}
+
+func (r *PricingRuleRepository) Create(rule *domain.PricingRule) error {
+	const query = `
+		INSERT INTO space_pricing_rules (space_id, name, date_from, date_to, days_of_week, price, adjust_pct, priority)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at`
+
+	var days any
+	if len(rule.DaysOfWeek) > 0 {
+		arr := make(pq.Int64Array, len(rule.DaysOfWeek))
+		for i, d := range rule.DaysOfWeek {
+			arr[i] = int64(d)
+		}
+		days = arr
+	}
+
+	return r.db.QueryRow(
+		query,
+		rule.SpaceID,
+		rule.Name,
+		rule.DateFrom,
+		rule.DateTo,
+		days,
+		rule.Price,
+		rule.AdjustPct,
+		rule.Priority,
+	).Scan(&rule.ID, &rule.CreatedAt)
+}
+
+// ListBySpace возвращает правила пространства в порядке применения
+func (r *PricingRuleRepository) ListBySpace(spaceID int) ([]domain.PricingRule, error) {
+	const query = `
+		SELECT id, space_id, name, date_from, date_to, days_of_week, price, adjust_pct, priority, created_at
+		FROM space_pricing_rules
+		WHERE space_id = $1
+		ORDER BY priority DESC, id DESC`
+
+	rows, err := r.db.Query(query, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.PricingRule
+	for rows.Next() {
+		var (
+			rule domain.PricingRule
+			days pq.Int64Array
+		)
+		if err := rows.Scan(
+			&rule.ID,
+			&rule.SpaceID,
+			&rule.Name,
+			&rule.DateFrom,
+			&rule.DateTo,
+			&days,
+			&rule.Price,
+			&rule.AdjustPct,
+			&rule.Priority,
+			&rule.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		for _, d := range days {
+			rule.DaysOfWeek = append(rule.DaysOfWeek, int(d))
+		}
+		res = append(res, rule)
+	}
+	return res, rows.Err()
+}
+
+func (r *PricingRuleRepository) Delete(id, spaceID int) error {
+	res, err := r.db.Exec(`DELETE FROM space_pricing_rules WHERE id = $1 AND space_id = $2`, id, spaceID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrPricingRuleNotFound
+	}
+	return nil
+}
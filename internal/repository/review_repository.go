@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"SpaceBookProject/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrReviewNotFound = errors.New("review not found")
+	ErrReviewExists   = errors.New("review for this booking already exists")
+	ErrReplyExists    = errors.New("review already has a reply")
+)
+
+const reviewColumns = `id, booking_id, direction, author_id, space_id, tenant_id, rating, comment, reply, replied_at, created_at`
+
+type ReviewRepository struct {
+	db *sql.DB
+}
+
+func NewReviewRepository(db *sql.DB) *ReviewRepository {
+	return &ReviewRepository{db: db}
+}
+
+func scanReview(row rowScanner, rv *domain.Review) error {
+	return row.Scan(
+		&rv.ID, &rv.BookingID, &rv.Direction, &rv.AuthorID,
+		&rv.SpaceID, &rv.TenantID, &rv.Rating, &rv.Comment,
+		&rv.Reply, &rv.RepliedAt, &rv.CreatedAt,
+	)
+}
+
+// Create сохраняет отзыв и в той же транзакции пересчитывает средний рейтинг
+// пространства или арендатора, в зависимости от направления отзыва.
+// Строка пространства или арендатора блокируется до вставки, поэтому отзывы
+// об одной цели пересчитывают рейтинг по очереди.
+func (r *ReviewRepository) Create(rv *domain.Review) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Блокируем строку, чей рейтинг пересчитываем: иначе два одновременных отзыва
+	// посчитают агрегат каждый без чужой, ещё не зафиксированной строки
+	lockQuery := `SELECT 1 FROM spaces WHERE id = $1 FOR UPDATE`
+	target := rv.SpaceID
+	if rv.Direction == domain.ReviewOfTenant {
+		lockQuery = `SELECT 1 FROM users WHERE id = $1 FOR UPDATE`
+		target = rv.TenantID
+	}
+	if _, err = tx.Exec(lockQuery, target); err != nil {
+		return err
+	}
+
+	const insertQuery = `
+		INSERT INTO reviews (booking_id, direction, author_id, space_id, tenant_id, rating, comment)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at`
+
+	err = tx.QueryRow(
+		insertQuery,
+		rv.BookingID,
+		rv.Direction,
+		rv.AuthorID,
+		rv.SpaceID,
+		rv.TenantID,
+		rv.Rating,
+		rv.Comment,
+	).Scan(&rv.ID, &rv.CreatedAt)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		err = ErrReviewExists
+	}
+	if err != nil {
+		return err
+	}
+
+	if rv.Direction == domain.ReviewOfSpace {
+		const spaceRating = `
+			UPDATE spaces
+			SET rating_avg = COALESCE(agg.avg, 0), rating_count = agg.cnt
+			FROM (
+				SELECT ROUND(AVG(rating), 2) AS avg, COUNT(*) AS cnt
+				FROM reviews
+				WHERE space_id = $1 AND direction = 'space'
+			) agg
+			WHERE id = $1`
+		_, err = tx.Exec(spaceRating, rv.SpaceID)
+	} else {
+		const tenantRating = `
+			UPDATE users
+			SET rating_avg = COALESCE(agg.avg, 0), rating_count = agg.cnt
+			FROM (
+				SELECT ROUND(AVG(rating), 2) AS avg, COUNT(*) AS cnt
+				FROM reviews
+				WHERE tenant_id = $1 AND direction = 'tenant'
+			) agg
+			WHERE id = $1`
+		_, err = tx.Exec(tenantRating, rv.TenantID)
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *ReviewRepository) GetByID(id int) (*domain.Review, error) {
+	query := `SELECT ` + reviewColumns + ` FROM reviews WHERE id = $1`
+
+	rv := &domain.Review{}
+	err := scanReview(r.db.QueryRow(query, id), rv)
+	if err == sql.ErrNoRows {
+		return nil, ErrReviewNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rv, nil
+}
+
+// ListBySpace возвращает отзывы арендаторов о пространстве, новые сверху
+func (r *ReviewRepository) ListBySpace(spaceID int) ([]domain.Review, error) {
+	query := `
+		SELECT ` + reviewColumns + `
+		FROM reviews
+		WHERE space_id = $1 AND direction = 'space'
+		ORDER BY created_at DESC, id DESC`
+	return r.queryReviews(query, spaceID)
+}
+
+// ListByTenant возвращает отзывы владельцев об арендаторе, новые сверху
+func (r *ReviewRepository) ListByTenant(tenantID int) ([]domain.Review, error) {
+	query := `
+		SELECT ` + reviewColumns + `
+		FROM reviews
+		WHERE tenant_id = $1 AND direction = 'tenant'
+		ORDER BY created_at DESC, id DESC`
+	return r.queryReviews(query, tenantID)
+}
+
+func (r *ReviewRepository) queryReviews(query string, args ...any) ([]domain.Review, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.Review
+	for rows.Next() {
+		var rv domain.Review
+		if err := scanReview(rows, &rv); err != nil {
+			return nil, err
+		}
+		res = append(res, rv)
+	}
+	return res, rows.Err()
+}
+
+// SetReply сохраняет ответ владельца. Ответ можно оставить только один раз.
+func (r *ReviewRepository) SetReply(id int, reply string) (*domain.Review, error) {
+	query := `
+		UPDATE reviews
+		SET reply = $1, replied_at = NOW()
+		WHERE id = $2 AND reply IS NULL
+		RETURNING ` + reviewColumns
+
+	rv := &domain.Review{}
+	err := scanReview(r.db.QueryRow(query, reply, id), rv)
+	if err == sql.ErrNoRows {
+		if _, getErr := r.GetByID(id); getErr != nil {
+			return nil, getErr
+		}
+		return nil, ErrReplyExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rv, nil
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"SpaceBookProject/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+var ErrSavedSearchNotFound = errors.New("saved search not found")
+
+const savedSearchColumns = `id, user_id, name, filter, is_paused, checked_at, created_at, updated_at`
+
+type SavedSearchRepository struct {
+	db *sql.DB
+}
+
+func NewSavedSearchRepository(db *sql.DB) *SavedSearchRepository {
+	return &SavedSearchRepository{db: db}
+}
+
+func scanSavedSearch(row rowScanner, s *domain.SavedSearch) error {
+	var filter []byte
+	if err := row.Scan(&s.ID, &s.UserID, &s.Name, &filter, &s.IsPaused, &s.CheckedAt, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		return err
+	}
+	return json.Unmarshal(filter, &s.Filter)
+}
+
+func (r *SavedSearchRepository) Create(s *domain.SavedSearch) error {
+	filter, err := json.Marshal(s.Filter)
+	if err != nil {
+		return err
+	}
+
+	const query = `
+		INSERT INTO saved_searches (user_id, name, filter)
+		VALUES ($1, $2, $3)
+		RETURNING id, is_paused, checked_at, created_at, updated_at`
+
+	return r.db.QueryRow(query, s.UserID, s.Name, filter).
+		Scan(&s.ID, &s.IsPaused, &s.CheckedAt, &s.CreatedAt, &s.UpdatedAt)
+}
+
+func (r *SavedSearchRepository) GetByID(id int) (*domain.SavedSearch, error) {
+	s := &domain.SavedSearch{}
+	err := scanSavedSearch(r.db.QueryRow(`SELECT `+savedSearchColumns+` FROM saved_searches WHERE id = $1`, id), s)
+	if err == sql.ErrNoRows {
+		return nil, ErrSavedSearchNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (r *SavedSearchRepository) ListByUser(userID int) ([]domain.SavedSearch, error) {
+	return r.query(`SELECT `+savedSearchColumns+` FROM saved_searches WHERE user_id = $1 ORDER BY created_at DESC, id DESC`, userID)
+}
+
+// ListActive возвращает поиски, которые не стоят на паузе
+func (r *SavedSearchRepository) ListActive() ([]domain.SavedSearch, error) {
+	return r.query(`SELECT ` + savedSearchColumns + ` FROM saved_searches WHERE NOT is_paused ORDER BY id`)
+}
+
+func (r *SavedSearchRepository) query(query string, args ...any) ([]domain.SavedSearch, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []domain.SavedSearch{}
+	for rows.Next() {
+		var s domain.SavedSearch
+		if err := scanSavedSearch(rows, &s); err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+	return result, rows.Err()
+}
+
+// SetPaused ставит поиск на паузу или снимает с неё. При снятии checked_at сдвигается
+// на текущий момент: о пространствах, появившихся во время паузы, не уведомляем.
+func (r *SavedSearchRepository) SetPaused(id int, paused bool) error {
+	const query = `
+		UPDATE saved_searches
+		SET checked_at = CASE WHEN is_paused AND NOT $1 THEN NOW() ELSE checked_at END,
+		    is_paused = $1, updated_at = NOW()
+		WHERE id = $2`
+
+	res, err := r.db.Exec(query, paused, id)
+	if err != nil {
+		return err
+	}
+	return savedSearchAffected(res)
+}
+
+func (r *SavedSearchRepository) Delete(id int) error {
+	res, err := r.db.Exec(`DELETE FROM saved_searches WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	return savedSearchAffected(res)
+}
+
+func savedSearchAffected(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrSavedSearchNotFound
+	}
+	return nil
+}
+
+// AddMatches запоминает найденные пространства, сдвигает checked_at и в той же транзакции
+// ставит в notification_outbox уведомления о новых совпадениях: совпадение считается
+// отправленным, только если уведомление сохранено. Возвращает новые пространства.
+func (r *SavedSearchRepository) AddMatches(search *domain.SavedSearch, spaceIDs []int, checkedAt time.Time) (newIDs []int, err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if len(spaceIDs) > 0 {
+		ids := make(pq.Int64Array, len(spaceIDs))
+		for i, v := range spaceIDs {
+			ids[i] = int64(v)
+		}
+
+		rows, err := tx.Query(`
+			INSERT INTO saved_search_matches (saved_search_id, space_id)
+			SELECT $1, unnest($2::INTEGER[])
+			ON CONFLICT (saved_search_id, space_id) DO NOTHING
+			RETURNING space_id`, search.ID, ids)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var spaceID int
+			if err := rows.Scan(&spaceID); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			newIDs = append(newIDs, spaceID)
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, spaceID := range newIDs {
+		err = enqueueNotificationTx(tx, domain.NotificationEvent{
+			Type:          domain.NotificationSavedSearchMatch,
+			UserID:        search.UserID,
+			SpaceID:       spaceID,
+			SavedSearchID: search.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err = tx.Exec(`UPDATE saved_searches SET checked_at = $1 WHERE id = $2`, checkedAt, search.ID); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return newIDs, nil
+}
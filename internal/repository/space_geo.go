@@ -0,0 +1,42 @@
+package repository
+
+import "fmt"
+
+// GeoQuery строит SQL для расстояния до пространства. По умолчанию используется
+// формула гаверсинусов на обычном Postgres; при наличии PostGIS можно подключить PostGISGeo.
+type GeoQuery interface {
+	// DistanceKm — выражение расстояния в километрах от точки ($latArg, $lngArg)
+	// до колонок latitude/longitude таблицы spaces
+	DistanceKm(latArg, lngArg int) string
+}
+
+type HaversineGeo struct{}
+
+func (HaversineGeo) DistanceKm(latArg, lngArg int) string {
+	// LEAST защищает ASIN от значений чуть больше 1 из-за погрешности округления
+	return fmt.Sprintf(`(6371 * 2 * ASIN(LEAST(1, SQRT(
+		POWER(SIN(RADIANS(latitude - $%[1]d) / 2), 2) +
+		COS(RADIANS($%[1]d)) * COS(RADIANS(latitude)) *
+		POWER(SIN(RADIANS(longitude - $%[2]d) / 2), 2)))))`, latArg, lngArg)
+}
+
+// PostGISGeo требует расширения postgis
+type PostGISGeo struct{}
+
+func (PostGISGeo) DistanceKm(latArg, lngArg int) string {
+	return fmt.Sprintf(`(ST_DistanceSphere(
+		ST_MakePoint(longitude, latitude),
+		ST_MakePoint($%d, $%d)) / 1000)`, lngArg, latArg)
+}
+
+// NewGeoQuery выбирает реализацию по имени из конфигурации
+func NewGeoQuery(backend string) (GeoQuery, error) {
+	switch backend {
+	case "", "haversine":
+		return HaversineGeo{}, nil
+	case "postgis":
+		return PostGISGeo{}, nil
+	default:
+		return nil, fmt.Errorf("unknown geo backend %q", backend)
+	}
+}
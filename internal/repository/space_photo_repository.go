@@ -0,0 +1,263 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"SpaceBookProject/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrPhotoNotFound      = errors.New("photo not found")
+	ErrPhotoOrderMismatch = errors.New("photo_ids must list every photo of the space exactly once")
+	ErrTooManyPhotos      = errors.New("space already has the maximum number of photos")
+)
+
+const photoColumns = `id, space_id, storage_key, thumb_key, content_type, size_bytes, width, height, position, is_cover, created_at`
+
+type SpacePhotoRepository struct {
+	db *sql.DB
+}
+
+func NewSpacePhotoRepository(db *sql.DB) *SpacePhotoRepository {
+	return &SpacePhotoRepository{db: db}
+}
+
+func scanPhoto(row rowScanner, p *domain.SpacePhoto) error {
+	return row.Scan(
+		&p.ID, &p.SpaceID, &p.StorageKey, &p.ThumbKey, &p.ContentType,
+		&p.SizeBytes, &p.Width, &p.Height, &p.Position, &p.IsCover, &p.CreatedAt,
+	)
+}
+
+// Create добавляет фото в конец списка; первое фото пространства становится обложкой.
+// Строка пространства блокируется до подсчёта, поэтому параллельные загрузки
+// не выберут две обложки и не превысят maxPhotos.
+func (r *SpacePhotoRepository) Create(p *domain.SpacePhoto, maxPhotos int) (err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var locked int
+	err = tx.QueryRow(`SELECT id FROM spaces WHERE id = $1 FOR UPDATE`, p.SpaceID).Scan(&locked)
+	if err == sql.ErrNoRows {
+		err = ErrSpaceNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	var count int
+	if err = tx.QueryRow(`SELECT COUNT(*) FROM space_photos WHERE space_id = $1`, p.SpaceID).Scan(&count); err != nil {
+		return err
+	}
+	if count >= maxPhotos {
+		err = ErrTooManyPhotos
+		return err
+	}
+
+	const query = `
+		INSERT INTO space_photos (space_id, storage_key, thumb_key, content_type, size_bytes, width, height, position, is_cover)
+		SELECT $1, $2, $3, $4, $5, $6, $7,
+		       COALESCE(MAX(position) + 1, 0),
+		       COUNT(*) = 0
+		FROM space_photos
+		WHERE space_id = $1
+		RETURNING id, position, is_cover, created_at`
+
+	err = tx.QueryRow(
+		query,
+		p.SpaceID,
+		p.StorageKey,
+		p.ThumbKey,
+		p.ContentType,
+		p.SizeBytes,
+		p.Width,
+		p.Height,
+	).Scan(&p.ID, &p.Position, &p.IsCover, &p.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *SpacePhotoRepository) CountBySpace(spaceID int) (int, error) {
+	var n int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM space_photos WHERE space_id = $1`, spaceID).Scan(&n)
+	return n, err
+}
+
+func (r *SpacePhotoRepository) ListBySpace(spaceID int) ([]domain.SpacePhoto, error) {
+	byspace, err := r.ListBySpaces([]int{spaceID})
+	if err != nil {
+		return nil, err
+	}
+	return byspace[spaceID], nil
+}
+
+// ListBySpaces загружает фото сразу для нескольких пространств, чтобы не делать запрос на каждое
+func (r *SpacePhotoRepository) ListBySpaces(spaceIDs []int) (map[int][]domain.SpacePhoto, error) {
+	res := make(map[int][]domain.SpacePhoto, len(spaceIDs))
+	if len(spaceIDs) == 0 {
+		return res, nil
+	}
+
+	ids := make(pq.Int64Array, len(spaceIDs))
+	for i, id := range spaceIDs {
+		ids[i] = int64(id)
+	}
+
+	query := `
+		SELECT ` + photoColumns + `
+		FROM space_photos
+		WHERE space_id = ANY($1)
+		ORDER BY space_id, position, id`
+
+	rows, err := r.db.Query(query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p domain.SpacePhoto
+		if err := scanPhoto(rows, &p); err != nil {
+			return nil, err
+		}
+		res[p.SpaceID] = append(res[p.SpaceID], p)
+	}
+	return res, rows.Err()
+}
+
+// Delete удаляет фото и возвращает его, чтобы можно было удалить файлы.
+// Если удалили обложку, ею становится первое оставшееся фото.
+func (r *SpacePhotoRepository) Delete(id, spaceID int) (*domain.SpacePhoto, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	query := `DELETE FROM space_photos WHERE id = $1 AND space_id = $2 RETURNING ` + photoColumns
+
+	p := &domain.SpacePhoto{}
+	err = scanPhoto(tx.QueryRow(query, id, spaceID), p)
+	if err == sql.ErrNoRows {
+		err = ErrPhotoNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if p.IsCover {
+		const coverQuery = `
+			UPDATE space_photos
+			SET is_cover = TRUE
+			WHERE id = (
+				SELECT id FROM space_photos
+				WHERE space_id = $1
+				ORDER BY position, id
+				LIMIT 1
+			)`
+		if _, err = tx.Exec(coverQuery, spaceID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// SetCover делает фото обложкой пространства
+func (r *SpacePhotoRepository) SetCover(id, spaceID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Сначала снимаем старую обложку, иначе сработает уникальный индекс
+	const resetQuery = `UPDATE space_photos SET is_cover = FALSE WHERE space_id = $1 AND is_cover AND id <> $2`
+	if _, err = tx.Exec(resetQuery, spaceID, id); err != nil {
+		return err
+	}
+
+	res, err := tx.Exec(`UPDATE space_photos SET is_cover = TRUE WHERE id = $1 AND space_id = $2`, id, spaceID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		err = ErrPhotoNotFound
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Reorder выставляет позиции фото в порядке photoIDs
+func (r *SpacePhotoRepository) Reorder(spaceID int, photoIDs []int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	ids := make(pq.Int64Array, len(photoIDs))
+	for i, id := range photoIDs {
+		ids[i] = int64(id)
+	}
+
+	// Позиция = индекс в массиве; лишние или чужие ID не обновятся и будут замечены по числу строк
+	const query = `
+		UPDATE space_photos p
+		SET position = o.ord - 1
+		FROM unnest($2::int[]) WITH ORDINALITY AS o(id, ord)
+		WHERE p.id = o.id AND p.space_id = $1`
+
+	res, err := tx.Exec(query, spaceID, ids)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	var total int
+	if err = tx.QueryRow(`SELECT COUNT(*) FROM space_photos WHERE space_id = $1`, spaceID).Scan(&total); err != nil {
+		return err
+	}
+	if int(n) != total || len(photoIDs) != total {
+		err = ErrPhotoOrderMismatch
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -2,51 +2,216 @@ package repository
 
 import (
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"html"
+	"strconv"
 	"strings"
 	"time"
 
 	"SpaceBookProject/internal/domain"
+	"SpaceBookProject/internal/geo"
+
+	"github.com/lib/pq"
 )
 
+// Amenities — коды удобств, которые должны быть у пространства все сразу.
+// Near с RadiusKm ограничивает выдачу кругом и сортирует её по расстоянию,
+// Box — прямоугольником карты.
+// Sort по умолчанию — newest, при поиске рядом с точкой — distance,
+// при полнотекстовом поиске — relevance.
+// AvailableFrom/AvailableTo (задаются вместе) исключают пространства
+// с одобренной бронью, пересекающей период [from, to).
 type SpaceFilter struct {
-	Query    *string
-	MinPrice *int
-	MaxPrice *int
-	MinArea  *float64
-	MaxArea  *float64
+	Query     *string
+	MinPrice  *int
+	MaxPrice  *int
+	MinArea   *float64
+	MaxArea   *float64
+	Amenities []string
+	Near      *geo.Point
+	RadiusKm  float64
+	Box       *geo.Box
+
+	AvailableFrom *time.Time
+	AvailableTo   *time.Time
+	// Только пространства, появившиеся в каталоге позже этого момента
+	ListedAfter *time.Time
+
+	Sort SpaceSort
 }
 
-var ErrSpaceNotFound = errors.New("space not found")
+type SpaceSort string
+
+const (
+	SpaceSortNewest    SpaceSort = "newest"
+	SpaceSortPriceAsc  SpaceSort = "price_asc"
+	SpaceSortPriceDesc SpaceSort = "price_desc"
+	SpaceSortAreaAsc   SpaceSort = "area_asc"
+	SpaceSortAreaDesc  SpaceSort = "area_desc"
+	SpaceSortRating    SpaceSort = "rating"
+	// Только вместе с Near
+	SpaceSortDistance SpaceSort = "distance"
+	// Только вместе с Query
+	SpaceSortRelevance SpaceSort = "relevance"
+)
+
+// DefaultTextSearchConfig создаётся миграцией 0023 и по ней строится search_vector
+const DefaultTextSearchConfig = "spacebook"
+
+// ts_headline отмечает совпадения управляющими символами, а не <mark>: описание пишет
+// владелец, поэтому фрагмент сначала экранируется и только потом маркеры заменяются тегами
+const (
+	headlineStart   = "\x02"
+	headlineStop    = "\x03"
+	headlineOptions = "StartSel=" + headlineStart + ", StopSel=" + headlineStop + ", MaxWords=35, MinWords=15, MaxFragments=2"
+)
+
+// highlightSnippet экранирует HTML во фрагменте и оборачивает совпадения в <mark>
+func highlightSnippet(snippet *string) {
+	if snippet == nil {
+		return
+	}
+	v := html.EscapeString(*snippet)
+	v = strings.ReplaceAll(v, headlineStart, "<mark>")
+	v = strings.ReplaceAll(v, headlineStop, "</mark>")
+	*snippet = v
+}
+
+// spaceSortKey — выражение сортировки и значение ключа для курсора
+type spaceSortKey struct {
+	expr  string
+	desc  bool
+	value func(s *domain.Space) string
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+var spaceSortKeys = map[SpaceSort]spaceSortKey{
+	SpaceSortNewest: {"created_at", true, func(s *domain.Space) string {
+		return s.CreatedAt.Format(time.RFC3339Nano)
+	}},
+	SpaceSortPriceAsc:  {"price", false, func(s *domain.Space) string { return strconv.Itoa(s.Price) }},
+	SpaceSortPriceDesc: {"price", true, func(s *domain.Space) string { return strconv.Itoa(s.Price) }},
+	SpaceSortAreaAsc:   {"area_m2", false, func(s *domain.Space) string { return formatFloat(s.AreaM2) }},
+	SpaceSortAreaDesc:  {"area_m2", true, func(s *domain.Space) string { return formatFloat(s.AreaM2) }},
+	SpaceSortRating:    {"rating_avg", true, func(s *domain.Space) string { return formatFloat(s.RatingAvg) }},
+	// Выражения расстояния и релевантности подставляются при построении запроса
+	SpaceSortDistance:  {"", false, func(s *domain.Space) string { return formatFloat(*s.DistanceKm) }},
+	SpaceSortRelevance: {"", true, func(s *domain.Space) string { return formatFloat(*s.Rank) }},
+}
+
+var (
+	ErrSpaceNotFound          = errors.New("space not found")
+	ErrSpaceHasActiveBookings = errors.New("space has upcoming approved bookings")
+	ErrInvalidSort            = errors.New("invalid sort")
+)
+
+const spaceColumns = `id, owner_id, title, description, area_m2, price, currency, cleaning_fee,
+	weekly_discount_pct, monthly_discount_pct, booking_unit, min_slot_minutes, cancellation_policy, cancellation_tiers,
+	phone, address_line, city, region, postal_code, country, latitude, longitude, owner_cancellations, rating_avg, rating_count, is_active, created_at, updated_at`
 
 type SpaceRepository struct {
-	db *sql.DB
+	db         *sql.DB
+	geo        GeoQuery
+	textConfig string
 }
 
 func NewSpaceRepository(db *sql.DB) *SpaceRepository {
-	return &SpaceRepository{db: db}
+	return &SpaceRepository{db: db, geo: HaversineGeo{}, textConfig: DefaultTextSearchConfig}
 }
 
-func (r *SpaceRepository) GetByID(id int) (*domain.Space, error) {
-	const query = `
-        SELECT id, owner_id, title, description, area_m2, price, phone, created_at, updated_at
-        FROM spaces
-        WHERE id = $1
-    `
+// UseGeo подменяет способ расчёта расстояний (например, на PostGIS)
+func (r *SpaceRepository) UseGeo(g GeoQuery) {
+	r.geo = g
+}
 
-	s := &domain.Space{}
-	err := r.db.QueryRow(query, id).Scan(
+// UseTextSearchConfig задаёт конфигурацию Postgres, которой разбирается запрос q.
+// Она должна совпадать с той, по которой построена колонка search_vector.
+func (r *SpaceRepository) UseTextSearchConfig(config string) {
+	r.textConfig = config
+}
+
+// rowScanner объединяет *sql.Row и *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// qualifyColumns добавляет алиас таблицы к каждой колонке списка
+func qualifyColumns(columns, alias string) string {
+	cols := strings.Split(columns, ",")
+	for i, c := range cols {
+		cols[i] = alias + "." + strings.TrimSpace(c)
+	}
+	return strings.Join(cols, ", ")
+}
+
+// tiersJSON читает и пишет ступени политики отмены в колонку JSONB; пустой список хранится как NULL
+type tiersJSON struct {
+	tiers *[]domain.CancellationTier
+}
+
+func (t tiersJSON) Scan(src any) error {
+	if src == nil {
+		*t.tiers = nil
+		return nil
+	}
+	b, ok := src.([]byte)
+	if !ok {
+		return fmt.Errorf("cancellation tiers: unexpected type %T", src)
+	}
+	return json.Unmarshal(b, t.tiers)
+}
+
+func (t tiersJSON) Value() (driver.Value, error) {
+	if len(*t.tiers) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(*t.tiers)
+}
+
+func scanSpace(row rowScanner, s *domain.Space) error {
+	return row.Scan(
 		&s.ID,
 		&s.OwnerID,
 		&s.Title,
 		&s.Description,
 		&s.AreaM2,
 		&s.Price,
+		&s.Currency,
+		&s.CleaningFee,
+		&s.WeeklyDiscountPct,
+		&s.MonthlyDiscountPct,
+		&s.BookingUnit,
+		&s.MinSlotMinutes,
+		&s.CancellationPolicy,
+		tiersJSON{&s.CancellationTiers},
 		&s.Phone,
+		&s.Address.Line,
+		&s.Address.City,
+		&s.Address.Region,
+		&s.Address.PostalCode,
+		&s.Address.Country,
+		&s.Latitude,
+		&s.Longitude,
+		&s.OwnerCancellations,
+		&s.RatingAvg,
+		&s.RatingCount,
+		&s.IsActive,
 		&s.CreatedAt,
 		&s.UpdatedAt,
 	)
+}
+
+func (r *SpaceRepository) GetByID(id int) (*domain.Space, error) {
+	query := `SELECT ` + spaceColumns + ` FROM spaces WHERE id = $1`
+
+	s := &domain.Space{}
+	err := scanSpace(r.db.QueryRow(query, id), s)
 	if err == sql.ErrNoRows {
 		return nil, ErrSpaceNotFound
 	}
@@ -56,21 +221,70 @@ func (r *SpaceRepository) GetByID(id int) (*domain.Space, error) {
 	return s, nil
 }
 
-func (r *SpaceRepository) ListFiltered(f SpaceFilter) ([]domain.Space, error) {
-	query := `
-		SELECT id, owner_id, title, description, area_m2, price, phone, created_at, updated_at
-		FROM spaces
-	`
+// boxCond — условие попадания координат в прямоугольник; при пересечении
+// 180-го меридиана долгота проверяется через OR
+func boxCond(b geo.Box, i int) (string, []any) {
+	lngOp := "AND"
+	if b.CrossesAntimeridian() {
+		lngOp = "OR"
+	}
+	cond := fmt.Sprintf("latitude BETWEEN $%d AND $%d AND (longitude >= $%d %s longitude <= $%d)",
+		i, i+1, i+2, lngOp, i+3)
+	return cond, []any{b.MinLat, b.MaxLat, b.MinLng, b.MaxLng}
+}
+
+// ListFiltered возвращает публичный каталог: архивные пространства в него не попадают.
+// При поиске рядом с точкой у результатов заполняется DistanceKm,
+// при полнотекстовом поиске — Rank и Snippet.
+func (r *SpaceRepository) ListFiltered(f SpaceFilter, page PageRequest) (*domain.Page[domain.Space], error) {
 	var (
-		conds []string
+		conds = []string{"is_active = TRUE"}
 		args  []any
 		i     = 1
 	)
 
+	distance := "NULL::DOUBLE PRECISION"
+	if f.Near != nil {
+		distance = r.geo.DistanceKm(i, i+1)
+		args = append(args, f.Near.Lat, f.Near.Lng)
+		i += 2
+
+		cond, boxArgs := boxCond(geo.BoundingBox(*f.Near, f.RadiusKm), i)
+		conds = append(conds, cond, fmt.Sprintf("%s <= $%d", distance, i+4))
+		args = append(args, append(boxArgs, f.RadiusKm)...)
+		i += 5
+	}
+	if f.AvailableFrom != nil && f.AvailableTo != nil {
+		conds = append(conds, fmt.Sprintf(`NOT EXISTS (
+			SELECT 1
+			FROM bookings b
+			WHERE b.space_id = spaces.id
+			  AND b.status = 'approved'
+			  AND NOT (b.date_to <= $%d OR b.date_from >= $%d))`, i, i+1))
+		args = append(args, *f.AvailableFrom, *f.AvailableTo)
+		i += 2
+	}
+	if f.ListedAfter != nil {
+		conds = append(conds, fmt.Sprintf("listed_at > $%d", i))
+		args = append(args, *f.ListedAfter)
+		i++
+	}
+	if f.Box != nil {
+		cond, boxArgs := boxCond(*f.Box, i)
+		conds = append(conds, cond)
+		args = append(args, boxArgs...)
+		i += 4
+	}
+
+	rank, snippet := "NULL::REAL", "NULL::TEXT"
 	if f.Query != nil && *f.Query != "" {
-		conds = append(conds, fmt.Sprintf("(title ILIKE $%d OR description ILIKE $%d)", i, i+1))
-		pattern := "%" + *f.Query + "%"
-		args = append(args, pattern, pattern)
+		tsquery := fmt.Sprintf("websearch_to_tsquery($%d::regconfig, $%d)", i, i+1)
+		conds = append(conds, "search_vector @@ "+tsquery)
+		rank = "ts_rank(search_vector, " + tsquery + ")"
+		// Маркеры, случайно оказавшиеся в самом тексте, убираем
+		snippet = fmt.Sprintf("ts_headline($%d::regconfig, translate(COALESCE(NULLIF(description, ''), title), '%s', ''), %s, '%s')",
+			i, headlineStart+headlineStop, tsquery, headlineOptions)
+		args = append(args, r.textConfig, *f.Query)
 		i += 2
 	}
 	if f.MinPrice != nil {
@@ -93,12 +307,68 @@ func (r *SpaceRepository) ListFiltered(f SpaceFilter) ([]domain.Space, error) {
 		args = append(args, *f.MaxArea)
 		i++
 	}
+	if len(f.Amenities) > 0 {
+		conds = append(conds, fmt.Sprintf(`id IN (
+			SELECT sa.space_id
+			FROM space_amenities sa
+			JOIN amenities a ON a.id = sa.amenity_id
+			WHERE a.code = ANY($%d)
+			GROUP BY sa.space_id
+			HAVING COUNT(*) = $%d)`, i, i+1))
+		args = append(args, pq.Array(f.Amenities), len(f.Amenities))
+		i += 2
+	}
+
+	where := " WHERE " + strings.Join(conds, " AND ")
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM spaces`+where, args...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	sort := f.Sort
+	if sort == "" {
+		switch {
+		case f.Near != nil:
+			sort = SpaceSortDistance
+		case f.Query != nil && *f.Query != "":
+			sort = SpaceSortRelevance
+		default:
+			sort = SpaceSortNewest
+		}
+	}
+	key, ok := spaceSortKeys[sort]
+	if !ok {
+		return nil, ErrInvalidSort
+	}
+	switch sort {
+	case SpaceSortDistance:
+		if f.Near == nil {
+			return nil, ErrInvalidSort
+		}
+		key.expr = distance
+	case SpaceSortRelevance:
+		if f.Query == nil || *f.Query == "" {
+			return nil, ErrInvalidSort
+		}
+		key.expr = rank
+	}
 
-	if len(conds) > 0 {
-		query += " WHERE " + strings.Join(conds, " AND ")
+	c, err := decodeCursor(page.Cursor, string(sort))
+	if err != nil {
+		return nil, err
+	}
+	if c != nil {
+		where += " AND " + keysetCond(key.expr, "id", key.desc, i)
+		args = append(args, c.Value, c.ID)
+		i += 2
 	}
 
-	query += " ORDER BY created_at DESC, id DESC"
+	limit := page.limit()
+	query := `SELECT ` + spaceColumns + `, ` + distance + ` AS distance_km, ` + rank + ` AS rank, ` +
+		snippet + ` AS snippet FROM spaces` + where +
+		orderBy(key.expr, "id", key.desc) + fmt.Sprintf(" LIMIT $%d", i)
+	args = append(args, limit+1)
 
 	rows, err := r.db.Query(query, args...)
 	if err != nil {
@@ -106,20 +376,72 @@ func (r *SpaceRepository) ListFiltered(f SpaceFilter) ([]domain.Space, error) {
 	}
 	defer rows.Close()
 
+	var items []domain.Space
+	for rows.Next() {
+		var s domain.Space
+		if err := scanSpace(extraScanner{rows, []any{&s.DistanceKm, &s.Rank, &s.Snippet}}, &s); err != nil {
+			return nil, err
+		}
+		highlightSnippet(s.Snippet)
+		items = append(items, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	items, next := nextCursor(items, limit, string(sort), func(s domain.Space) (string, int) {
+		return key.value(&s), s.ID
+	})
+	return &domain.Page[domain.Space]{Items: items, NextCursor: next, Total: total}, nil
+}
+
+// ownerSpacesSort — пространства владельца отдаются по дате создания, новые сверху
+const ownerSpacesSort = "created_at"
+
+// ListByOwner возвращает страницу пространств владельца, включая архивные
+func (r *SpaceRepository) ListByOwner(ownerID int, page PageRequest) (*domain.Page[domain.Space], error) {
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM spaces WHERE owner_id = $1`, ownerID).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	c, err := decodeCursor(page.Cursor, ownerSpacesSort)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := page.limit()
+	q := `SELECT ` + spaceColumns + ` FROM spaces WHERE owner_id = $1`
+	args := []any{ownerID}
+	if c != nil {
+		q += " AND " + keysetCond("created_at", "id", true, 2)
+		args = append(args, c.Value, c.ID)
+	}
+	q += orderBy("created_at", "id", true) + fmt.Sprintf(" LIMIT $%d", len(args)+1)
+	args = append(args, limit+1)
+
+	items, err := r.querySpaces(q, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	items, next := nextCursor(items, limit, ownerSpacesSort, func(s domain.Space) (string, int) {
+		return s.CreatedAt.Format(time.RFC3339Nano), s.ID
+	})
+	return &domain.Page[domain.Space]{Items: items, NextCursor: next, Total: total}, nil
+}
+
+func (r *SpaceRepository) querySpaces(query string, args ...any) ([]domain.Space, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
 	var result []domain.Space
 	for rows.Next() {
 		var s domain.Space
-		if err := rows.Scan(
-			&s.ID,
-			&s.OwnerID,
-			&s.Title,
-			&s.Description,
-			&s.AreaM2,
-			&s.Price,
-			&s.Phone,
-			&s.CreatedAt,
-			&s.UpdatedAt,
-		); err != nil {
+		if err := scanSpace(rows, &s); err != nil {
 			return nil, err
 		}
 		result = append(result, s)
@@ -127,25 +449,248 @@ func (r *SpaceRepository) ListFiltered(f SpaceFilter) ([]domain.Space, error) {
 	return result, rows.Err()
 }
 
-func (r *SpaceRepository) Create(space *domain.Space) error {
+// Create сохраняет пространство, первую точку истории цены и удобства в одной транзакции
+func (r *SpaceRepository) Create(space *domain.Space, amenities []string) (err error) {
 	now := time.Now()
 
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
 	query := `
-		INSERT INTO spaces (owner_id, title, description, area_m2, price, phone, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		RETURNING id, created_at, updated_at`
+		INSERT INTO spaces (owner_id, title, description, area_m2, price, currency, cleaning_fee,
+		                    weekly_discount_pct, monthly_discount_pct, booking_unit, min_slot_minutes,
+		                    cancellation_policy, cancellation_tiers, phone, address_line, city, region,
+		                    postal_code, country, latitude, longitude, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23)
+		RETURNING id, is_active, created_at, updated_at`
 
-	err := r.db.QueryRow(
+	err = tx.QueryRow(
 		query,
 		space.OwnerID,
 		space.Title,
 		space.Description,
 		space.AreaM2,
 		space.Price,
+		space.Currency,
+		space.CleaningFee,
+		space.WeeklyDiscountPct,
+		space.MonthlyDiscountPct,
+		space.BookingUnit,
+		space.MinSlotMinutes,
+		space.CancellationPolicy,
+		tiersJSON{&space.CancellationTiers},
 		space.Phone,
+		space.Address.Line,
+		space.Address.City,
+		space.Address.Region,
+		space.Address.PostalCode,
+		space.Address.Country,
+		space.Latitude,
+		space.Longitude,
 		now,
 		now,
-	).Scan(&space.ID, &space.CreatedAt, &space.UpdatedAt)
+	).Scan(&space.ID, &space.IsActive, &space.CreatedAt, &space.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err = recordPrice(tx, space); err != nil {
+		return err
+	}
+	if err = setSpaceAmenities(tx, space.ID, amenities); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Update сохраняет пространство; изменение цены или валюты попадает в историю цен.
+// amenities заменяет набор удобств в той же транзакции; nil — набор не меняется.
+func (r *SpaceRepository) Update(space *domain.Space, amenities []string) (err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
 
+	const query = `
+		UPDATE spaces
+		SET title = $1, description = $2, area_m2 = $3, price = $4, currency = $5, cleaning_fee = $6,
+		    weekly_discount_pct = $7, monthly_discount_pct = $8, booking_unit = $9, min_slot_minutes = $10,
+		    cancellation_policy = $11, cancellation_tiers = $12, phone = $13,
+		    address_line = $14, city = $15, region = $16, postal_code = $17, country = $18,
+		    latitude = $19, longitude = $20, updated_at = NOW()
+		WHERE id = $21
+		RETURNING updated_at`
+
+	err = tx.QueryRow(
+		query,
+		space.Title,
+		space.Description,
+		space.AreaM2,
+		space.Price,
+		space.Currency,
+		space.CleaningFee,
+		space.WeeklyDiscountPct,
+		space.MonthlyDiscountPct,
+		space.BookingUnit,
+		space.MinSlotMinutes,
+		space.CancellationPolicy,
+		tiersJSON{&space.CancellationTiers},
+		space.Phone,
+		space.Address.Line,
+		space.Address.City,
+		space.Address.Region,
+		space.Address.PostalCode,
+		space.Address.Country,
+		space.Latitude,
+		space.Longitude,
+		space.ID,
+	).Scan(&space.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrSpaceNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = recordPrice(tx, space); err != nil {
+		return err
+	}
+	if amenities != nil {
+		if err = setSpaceAmenities(tx, space.ID, amenities); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// recordPrice добавляет точку истории, если цена или валюта отличаются от последней записанной
+func recordPrice(tx *sql.Tx, space *domain.Space) error {
+	const query = `
+		INSERT INTO space_price_history (space_id, price, currency)
+		SELECT $1, $2, $3
+		WHERE NOT EXISTS (
+			SELECT 1
+			FROM (
+				SELECT price, currency
+				FROM space_price_history
+				WHERE space_id = $1
+				ORDER BY changed_at DESC, id DESC
+				LIMIT 1
+			) last
+			WHERE last.price = $2 AND last.currency = $3
+		)`
+
+	_, err := tx.Exec(query, space.ID, space.Price, space.Currency)
 	return err
 }
+
+// ListPriceHistory возвращает историю базовой цены, старые записи сверху
+func (r *SpaceRepository) ListPriceHistory(spaceID int) ([]domain.PricePoint, error) {
+	const query = `
+		SELECT price, currency, changed_at
+		FROM space_price_history
+		WHERE space_id = $1
+		ORDER BY changed_at ASC, id ASC`
+
+	rows, err := r.db.Query(query, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []domain.PricePoint{}
+	for rows.Next() {
+		var p domain.PricePoint
+		if err := rows.Scan(&p.Price, &p.Currency, &p.ChangedAt); err != nil {
+			return nil, err
+		}
+		result = append(result, p)
+	}
+	return result, rows.Err()
+}
+
+// SetActive архивирует (false) или возвращает в каталог (true) пространство.
+// Возврат из архива обновляет listed_at и забывает прежние совпадения
+// сохранённых поисков, чтобы о пространстве уведомили снова.
+func (r *SpaceRepository) SetActive(id int, active bool) (err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var wasActive bool
+	err = tx.QueryRow(`SELECT is_active FROM spaces WHERE id = $1 FOR UPDATE`, id).Scan(&wasActive)
+	if err == sql.ErrNoRows {
+		err = ErrSpaceNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	relisted := active && !wasActive
+	const query = `
+		UPDATE spaces
+		SET listed_at = CASE WHEN $1 THEN NOW() ELSE listed_at END,
+		    is_active = $2, updated_at = NOW()
+		WHERE id = $3`
+	if _, err = tx.Exec(query, relisted, active, id); err != nil {
+		return err
+	}
+
+	if relisted {
+		if _, err = tx.Exec(`DELETE FROM saved_search_matches WHERE space_id = $1`, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Delete удаляет пространство, если у него нет одобренных броней, которые ещё не закончились.
+// Проверка и удаление выполняются одним запросом, чтобы не было гонки с одобрением.
+func (r *SpaceRepository) Delete(id int) error {
+	const query = `
+		DELETE FROM spaces
+		WHERE id = $1
+		  AND NOT EXISTS (
+			SELECT 1
+			FROM bookings
+			WHERE space_id = $1
+			  AND status = 'approved'
+			  AND date_to > NOW()
+		  )`
+
+	res, err := r.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return nil
+	}
+
+	if _, err := r.GetByID(id); err != nil {
+		return err
+	}
+	return ErrSpaceHasActiveBookings
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"SpaceBookProject/internal/domain"
+)
+
+func TestCreateSpaceUnknownAmenity(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewSpaceRepository(db)
+
+	ownerID := createTestUser(t, db, domain.RoleOwner)
+	space := &domain.Space{
+		OwnerID:            ownerID,
+		Title:              "Test space",
+		AreaM2:             10,
+		Price:              1000,
+		Currency:           domain.DefaultCurrency,
+		BookingUnit:        domain.BookingUnitDay,
+		MinSlotMinutes:     domain.DefaultMinSlotMinutes,
+		CancellationPolicy: domain.CancellationFlexible,
+	}
+	if err := repo.Create(space, []string{"no-such-amenity"}); !errors.Is(err, ErrUnknownAmenity) {
+		t.Fatalf("create with unknown amenity: got %v, want ErrUnknownAmenity", err)
+	}
+
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM spaces WHERE owner_id = $1`, ownerID).Scan(&n); err != nil {
+		t.Fatalf("count spaces: %v", err)
+	}
+	if n != 0 {
+		t.Fatalf("%d spaces left after failed create, want 0", n)
+	}
+}
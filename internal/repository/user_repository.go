@@ -56,7 +56,7 @@ func (r *UserRepository) Create(user *domain.User) error {
 func (r *UserRepository) GetByEmail(email string) (*domain.User, error) {
 	user := &domain.User{}
 	query := `
-		SELECT id, email, password_hash, role, first_name, last_name, phone, created_at, updated_at
+		SELECT id, email, password_hash, role, first_name, last_name, phone, rating_avg, rating_count, created_at, updated_at
 		FROM users
 		WHERE email = $1`
 
@@ -68,6 +68,8 @@ func (r *UserRepository) GetByEmail(email string) (*domain.User, error) {
 		&user.FirstName,
 		&user.LastName,
 		&user.Phone,
+		&user.RatingAvg,
+		&user.RatingCount,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -85,7 +87,7 @@ func (r *UserRepository) GetByEmail(email string) (*domain.User, error) {
 func (r *UserRepository) GetByID(id int) (*domain.User, error) {
 	user := &domain.User{}
 	query := `
-		SELECT id, email, password_hash, role, first_name, last_name, phone, created_at, updated_at
+		SELECT id, email, password_hash, role, first_name, last_name, phone, rating_avg, rating_count, created_at, updated_at
 		FROM users
 		WHERE id = $1`
 
@@ -97,6 +99,8 @@ func (r *UserRepository) GetByID(id int) (*domain.User, error) {
 		&user.FirstName,
 		&user.LastName,
 		&user.Phone,
+		&user.RatingAvg,
+		&user.RatingCount,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
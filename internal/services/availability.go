@@ -0,0 +1,106 @@
+package services
+
+import (
+	"time"
+
+	"SpaceBookProject/internal/domain"
+	"SpaceBookProject/internal/pricing"
+)
+
+// Ограничения окна календаря, чтобы не строить его на годы вперёд:
+// для почасовых пространств календарь гораздо подробнее
+const (
+	maxAvailabilityDays       = 366
+	maxHourlyAvailabilityDays = 31
+)
+
+// GetAvailability строит календарь занятости пространства на период [from, to).
+// Одобренные брони дают "booked", ожидающие (если includePending) — "pending".
+func (s *BookingService) GetAvailability(spaceID int, from, to time.Time, includePending bool) (*domain.SpaceAvailability, error) {
+	if !from.Before(to) {
+		return nil, ErrInvalidDateRange
+	}
+
+	space, err := s.spaces.GetByID(spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	maxDays := maxAvailabilityDays
+	if space.BookingUnit == domain.BookingUnitHour {
+		maxDays = maxHourlyAvailabilityDays
+	}
+	if to.Sub(from) > time.Duration(maxDays)*24*time.Hour {
+		return nil, ErrDateRangeTooLong
+	}
+
+	rules, err := s.rules.ListBySpace(spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := []domain.BookingStatus{domain.BookingStatusApproved}
+	if includePending {
+		statuses = append(statuses, domain.BookingStatusPending)
+	}
+
+	bookings, err := s.bookings.ListOverlapping(spaceID, from, to, statuses)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.SpaceAvailability{
+		SpaceID: spaceID,
+		Unit:    space.BookingUnit,
+		From:    from,
+		To:      to,
+		Ranges: buildAvailabilityRanges(
+			from, to, pricing.SlotDuration(space.BookingUnit),
+			bookings, pricing.NewCalendar(space, rules),
+		),
+	}, nil
+}
+
+// buildAvailabilityRanges делит окно на слоты длиной step (сутки или час), размечает каждый
+// и склеивает соседние слоты с одинаковым статусом и ценой. Слот занят, если бронь
+// пересекается с ним хотя бы частично.
+func buildAvailabilityRanges(
+	from, to time.Time,
+	step time.Duration,
+	bookings []domain.Booking,
+	calendar *pricing.Calendar,
+) []domain.AvailabilityRange {
+	var ranges []domain.AvailabilityRange
+	for start := from; start.Before(to); start = start.Add(step) {
+		end := start.Add(step)
+		if end.After(to) {
+			end = to
+		}
+
+		mark := domain.AvailabilityFree
+		for _, b := range bookings {
+			if !b.DateFrom.Before(end) || !b.DateTo.After(start) {
+				continue
+			}
+			// Одобренная бронь важнее ожидающей
+			if b.Status == domain.BookingStatusApproved {
+				mark = domain.AvailabilityBooked
+				break
+			}
+			mark = domain.AvailabilityPending
+		}
+
+		price := calendar.PriceFor(start).Price
+		if n := len(ranges); n > 0 && ranges[n-1].Status == mark && ranges[n-1].Price == price {
+			ranges[n-1].To = end
+			continue
+		}
+		ranges = append(ranges, domain.AvailabilityRange{
+			From:   start,
+			To:     end,
+			Status: mark,
+			Price:  price,
+		})
+	}
+	return ranges
+}
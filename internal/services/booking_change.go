@@ -0,0 +1,157 @@
+package services
+
+import (
+	"time"
+
+	"SpaceBookProject/internal/domain"
+	"SpaceBookProject/internal/pricing"
+)
+
+// RequestReschedule создаёт заявку арендатора на перенос брони. Новые даты сразу проверяются
+// на пересечение и пересчитывается стоимость; сама бронь меняется только после решения владельца.
+func (s *BookingService) RequestReschedule(bookingID, tenantID int, req *domain.RescheduleBookingRequest) (*domain.BookingChangeRequest, error) {
+	b, err := s.bookings.GetByID(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if b.TenantID != tenantID {
+		return nil, ErrForbidden
+	}
+	if time.Now().After(b.DateFrom) {
+		return nil, ErrAlreadyStarted
+	}
+	if b.Status != domain.BookingStatusPending && b.Status != domain.BookingStatusApproved {
+		return nil, ErrWrongStatus
+	}
+
+	from, err := ParseBookingTime(req.DateFrom)
+	if err != nil {
+		return nil, err
+	}
+	to, err := ParseBookingTime(req.DateTo)
+	if err != nil {
+		return nil, err
+	}
+	if !from.After(time.Now()) {
+		return nil, ErrInvalidDateRange
+	}
+	if from.Equal(b.DateFrom) && to.Equal(b.DateTo) {
+		return nil, ErrSameDates
+	}
+
+	sp, err := s.spaces.GetByID(b.SpaceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPeriod(sp, from, to); err != nil {
+		return nil, err
+	}
+
+	overlap, err := s.bookings.HasApprovedOverlap(b.SpaceID, from, to, &b.ID)
+	if err != nil {
+		return nil, err
+	}
+	if overlap {
+		return nil, ErrOverlappingBooking
+	}
+
+	rules, err := s.rules.ListBySpace(sp.ID)
+	if err != nil {
+		return nil, err
+	}
+	quote, err := pricing.Quote(sp, rules, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	cr := &domain.BookingChangeRequest{
+		BookingID:   b.ID,
+		RequestedBy: tenantID,
+		OldDateFrom: b.DateFrom,
+		OldDateTo:   b.DateTo,
+		NewDateFrom: from,
+		NewDateTo:   to,
+		TotalPrice:  &quote.Total,
+		Currency:    &quote.Currency,
+		Reason:      req.Reason,
+	}
+	if err := s.bookings.CreateChangeRequest(cr); err != nil {
+		return nil, err
+	}
+
+	return cr, nil
+}
+
+// ApproveReschedule переносит бронь на даты из нерассмотренной заявки.
+// Пересечение с одобренными бронями проверяется повторно в момент переноса.
+func (s *BookingService) ApproveReschedule(bookingID, ownerID int, reason *string) (*domain.BookingChangeRequest, error) {
+	_, cr, err := s.pendingChangeForOwner(bookingID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.bookings.ApplyChangeRequest(cr.ID, ownerID, reason)
+}
+
+// DeclineReschedule отклоняет заявку на перенос; бронь остаётся в прежних датах
+func (s *BookingService) DeclineReschedule(bookingID, ownerID int) error {
+	_, cr, err := s.pendingChangeForOwner(bookingID, ownerID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.bookings.CloseChangeRequest(cr.ID, domain.BookingChangeDeclined, ownerID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// WithdrawReschedule позволяет арендатору отозвать свою заявку на перенос
+func (s *BookingService) WithdrawReschedule(bookingID, tenantID int) error {
+	b, err := s.bookings.GetByID(bookingID)
+	if err != nil {
+		return err
+	}
+	if b.TenantID != tenantID {
+		return ErrForbidden
+	}
+
+	cr, err := s.bookings.GetPendingChangeRequest(bookingID)
+	if err != nil {
+		return err
+	}
+	return s.bookings.CloseChangeRequest(cr.ID, domain.BookingChangeWithdrawn, tenantID)
+}
+
+// ListReschedules возвращает все заявки на перенос брони; доступ как у GetBookingHistory
+func (s *BookingService) ListReschedules(bookingID, userID int, userRole domain.UserRole) ([]domain.BookingChangeRequest, error) {
+	b, err := s.bookings.GetByID(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if userRole == domain.RoleTenant && b.TenantID != userID {
+		return nil, ErrForbidden
+	}
+	if userRole == domain.RoleOwner {
+		if err := s.checkSpaceOwner(b.SpaceID, userID); err != nil {
+			return nil, err
+		}
+	}
+	return s.bookings.ListChangeRequests(bookingID)
+}
+
+func (s *BookingService) pendingChangeForOwner(bookingID, ownerID int) (*domain.Booking, *domain.BookingChangeRequest, error) {
+	b, err := s.bookings.GetByID(bookingID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.checkSpaceOwner(b.SpaceID, ownerID); err != nil {
+		return nil, nil, err
+	}
+	cr, err := s.bookings.GetPendingChangeRequest(bookingID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return b, cr, nil
+}
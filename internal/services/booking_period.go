@@ -0,0 +1,51 @@
+package services
+
+import (
+	"time"
+
+	"SpaceBookProject/internal/domain"
+)
+
+// ParseBookingTime принимает дату YYYY-MM-DD (полночь UTC) или момент времени RFC3339
+func ParseBookingTime(v string) (time.Time, error) {
+	if t, err := time.Parse(dateLayout, v); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, ErrInvalidBookingTime
+	}
+	return t, nil
+}
+
+// Самая длинная бронь; заодно ограничивает разбивку цены по слотам
+const maxBookingDays = 366
+
+// checkPeriod проверяет, что период [from, to) можно забронировать в пространстве:
+// не длиннее maxBookingDays, посуточные брони начинаются и заканчиваются в полночь UTC,
+// почасовые — не короче минимального слота пространства
+func checkPeriod(space *domain.Space, from, to time.Time) error {
+	if !from.Before(to) {
+		return ErrInvalidDateRange
+	}
+	// Сравниваем через AddDate: на очень длинных периодах to.Sub(from) насыщается
+	if to.After(from.AddDate(0, 0, maxBookingDays)) {
+		return ErrDateRangeTooLong
+	}
+
+	if space.BookingUnit == domain.BookingUnitHour {
+		if to.Sub(from) < time.Duration(space.MinSlotMinutes)*time.Minute {
+			return ErrSlotTooShort
+		}
+		return nil
+	}
+
+	if !isMidnightUTC(from) || !isMidnightUTC(to) {
+		return ErrInvalidBookingTime
+	}
+	return nil
+}
+
+func isMidnightUTC(t time.Time) bool {
+	return t.UTC().Equal(t.UTC().Truncate(24 * time.Hour))
+}
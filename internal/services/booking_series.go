@@ -0,0 +1,259 @@
+package services
+
+import (
+	"time"
+
+	"SpaceBookProject/internal/domain"
+	"SpaceBookProject/internal/pricing"
+)
+
+// Верхняя граница числа повторений в одной серии (два года еженедельно)
+const maxSeriesOccurrences = 104
+
+type occurrence struct {
+	from, to time.Time
+}
+
+// CreateRecurringBooking создаёт серию броней по правилу повторения. Повторения, пересекающиеся
+// с одобренными бронями, возвращаются в Conflicts; без SkipConflicts серия при этом не создаётся
+// и возвращается ErrSeriesConflict вместе с деталями.
+func (s *BookingService) CreateRecurringBooking(tenantID int, req *domain.CreateRecurringBookingRequest) (*domain.BookingSeriesDetails, error) {
+	from, err := ParseBookingTime(req.DateFrom)
+	if err != nil {
+		return nil, err
+	}
+	to, err := ParseBookingTime(req.DateTo)
+	if err != nil {
+		return nil, err
+	}
+
+	sp, err := s.spaces.GetByID(req.SpaceID)
+	if err != nil {
+		return nil, err
+	}
+	if !sp.IsActive {
+		return nil, ErrSpaceInactive
+	}
+	if err := checkPeriod(sp, from, to); err != nil {
+		return nil, err
+	}
+
+	series := &domain.BookingSeries{
+		SpaceID:  sp.ID,
+		TenantID: tenantID,
+		Freq:     req.Recurrence.Freq,
+		Interval: req.Recurrence.Interval,
+		Count:    req.Recurrence.Count,
+	}
+	if series.Interval == 0 {
+		series.Interval = 1
+	}
+	if series.Until, err = parseOptionalDate(req.Recurrence.Until); err != nil {
+		return nil, ErrInvalidRecurrence
+	}
+
+	occurrences, err := expandRecurrence(series, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := s.rules.ListBySpace(sp.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	details := &domain.BookingSeriesDetails{}
+	var bookings []*domain.Booking
+	for _, occ := range occurrences {
+		overlap, err := s.bookings.HasApprovedOverlap(sp.ID, occ.from, occ.to, nil)
+		if err != nil {
+			return nil, err
+		}
+		if overlap {
+			details.Conflicts = append(details.Conflicts, domain.OccurrenceConflict{
+				DateFrom: occ.from,
+				DateTo:   occ.to,
+				Reason:   ErrOverlappingBooking.Error(),
+			})
+			continue
+		}
+
+		quote, err := pricing.Quote(sp, rules, occ.from, occ.to)
+		if err != nil {
+			return nil, err
+		}
+		b := &domain.Booking{
+			SpaceID:    sp.ID,
+			TenantID:   tenantID,
+			Status:     domain.BookingStatusPending,
+			DateFrom:   occ.from,
+			DateTo:     occ.to,
+			TotalPrice: &quote.Total,
+			Currency:   &quote.Currency,
+		}
+		snapshotCancellationPolicy(b, sp)
+		bookings = append(bookings, b)
+	}
+
+	if len(details.Conflicts) > 0 && (!req.SkipConflicts || len(bookings) == 0) {
+		details.Series = *series
+		return details, ErrSeriesConflict
+	}
+
+	if err := s.bookings.CreateSeries(series, bookings); err != nil {
+		return nil, err
+	}
+
+	details.Series = *series
+	for _, b := range bookings {
+		details.Bookings = append(details.Bookings, *b)
+	}
+	return details, nil
+}
+
+// GetSeries возвращает серию с повторениями; доступ как у GetBookingHistory
+func (s *BookingService) GetSeries(seriesID, userID int, userRole domain.UserRole) (*domain.BookingSeriesDetails, error) {
+	series, err := s.bookings.GetSeries(seriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	if userRole == domain.RoleTenant && series.TenantID != userID {
+		return nil, ErrForbidden
+	}
+	if userRole == domain.RoleOwner {
+		if err := s.checkSpaceOwner(series.SpaceID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	bookings, err := s.bookings.ListBySeries(seriesID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.BookingSeriesDetails{Series: *series, Bookings: bookings}, nil
+}
+
+// ApproveSeries одобряет все ожидающие повторения серии. Повторения, которые одобрить
+// не удалось (например, из-за пересечения), возвращаются в Conflicts.
+func (s *BookingService) ApproveSeries(seriesID, ownerID int, reason *string) (*domain.BookingSeriesDetails, error) {
+	return s.applyToSeries(seriesID, ownerID, domain.RoleOwner,
+		func(b domain.Booking) bool { return b.Status == domain.BookingStatusPending },
+		func(b domain.Booking) error { return s.ApproveBooking(b.ID, ownerID, reason) },
+	)
+}
+
+// RejectSeries отклоняет все ожидающие повторения серии
+func (s *BookingService) RejectSeries(seriesID, ownerID int, reason *string) (*domain.BookingSeriesDetails, error) {
+	return s.applyToSeries(seriesID, ownerID, domain.RoleOwner,
+		func(b domain.Booking) bool { return b.Status == domain.BookingStatusPending },
+		func(b domain.Booking) error { return s.RejectBooking(b.ID, ownerID, reason) },
+	)
+}
+
+// CancelSeries отменяет только будущие повторения; прошедшие и текущие остаются как есть
+func (s *BookingService) CancelSeries(seriesID, tenantID int, reason *string) (*domain.BookingSeriesDetails, error) {
+	now := time.Now()
+	return s.applyToSeries(seriesID, tenantID, domain.RoleTenant,
+		func(b domain.Booking) bool {
+			return b.DateFrom.After(now) &&
+				(b.Status == domain.BookingStatusPending || b.Status == domain.BookingStatusApproved)
+		},
+		func(b domain.Booking) error {
+			_, err := s.CancelBooking(b.ID, tenantID, reason)
+			return err
+		},
+	)
+}
+
+// applyToSeries применяет действие к подходящим повторениям серии и собирает ошибки по каждому
+func (s *BookingService) applyToSeries(
+	seriesID, userID int,
+	role domain.UserRole,
+	match func(domain.Booking) bool,
+	action func(domain.Booking) error,
+) (*domain.BookingSeriesDetails, error) {
+	details, err := s.GetSeries(seriesID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range details.Bookings {
+		if !match(b) {
+			continue
+		}
+		if err := action(b); err != nil {
+			id := b.ID
+			details.Conflicts = append(details.Conflicts, domain.OccurrenceConflict{
+				BookingID: &id,
+				DateFrom:  b.DateFrom,
+				DateTo:    b.DateTo,
+				Reason:    err.Error(),
+			})
+		}
+	}
+
+	// Возвращаем актуальные статусы повторений
+	if details.Bookings, err = s.bookings.ListBySeries(seriesID); err != nil {
+		return nil, err
+	}
+	return details, nil
+}
+
+// expandRecurrence разворачивает правило серии в список повторений. Для ежемесячных серий
+// месяцы без нужного числа (например, 31-го) пропускаются, как в RRULE.
+func expandRecurrence(series *domain.BookingSeries, from, to time.Time) ([]occurrence, error) {
+	if series.Until == nil && series.Count == nil {
+		return nil, ErrInvalidRecurrence
+	}
+
+	duration := to.Sub(from)
+	period := time.Duration(series.Interval) * 7 * 24 * time.Hour
+	if series.Freq == domain.RecurrenceMonthly {
+		period = time.Duration(series.Interval) * 28 * 24 * time.Hour
+	}
+	// Повторения одной серии не должны накладываться друг на друга
+	if duration > period {
+		return nil, ErrInvalidRecurrence
+	}
+
+	var res []occurrence
+	for i := 0; ; i++ {
+		var start time.Time
+		switch series.Freq {
+		case domain.RecurrenceWeekly:
+			start = from.AddDate(0, 0, 7*series.Interval*i)
+		case domain.RecurrenceMonthly:
+			start = from.AddDate(0, series.Interval*i, 0)
+			if start.Day() != from.Day() {
+				if i > maxSeriesOccurrences*2 {
+					return nil, ErrInvalidRecurrence
+				}
+				continue
+			}
+		default:
+			return nil, ErrInvalidRecurrence
+		}
+
+		if series.Until != nil && dateOf(start).After(*series.Until) {
+			break
+		}
+		if series.Count != nil && len(res) >= *series.Count {
+			break
+		}
+		if len(res) >= maxSeriesOccurrences {
+			return nil, ErrTooManyOccurrences
+		}
+		res = append(res, occurrence{from: start, to: start.Add(duration)})
+	}
+
+	if len(res) == 0 {
+		return nil, ErrInvalidRecurrence
+	}
+	return res, nil
+}
+
+// dateOf возвращает календарную дату момента t (в его часовом поясе) как полночь UTC
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
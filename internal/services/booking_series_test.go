@@ -0,0 +1,137 @@
+package services
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"SpaceBookProject/internal/domain"
+)
+
+func TestExpandRecurrence(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	count := func(n int) *int { return &n }
+	until := func(y int, m time.Month, d int) *time.Time {
+		t := day(y, m, d)
+		return &t
+	}
+
+	tests := []struct {
+		name     string
+		series   domain.BookingSeries
+		from, to time.Time
+
+		wantStarts []time.Time
+		wantLen    int
+		wantErr    error
+	}{
+		{
+			name:       "weekly by count",
+			series:     domain.BookingSeries{Freq: domain.RecurrenceWeekly, Interval: 1, Count: count(3)},
+			from:       day(2026, time.March, 2),
+			to:         day(2026, time.March, 3),
+			wantStarts: []time.Time{day(2026, time.March, 2), day(2026, time.March, 9), day(2026, time.March, 16)},
+		},
+		{
+			name:       "biweekly until inclusive",
+			series:     domain.BookingSeries{Freq: domain.RecurrenceWeekly, Interval: 2, Until: until(2026, time.March, 30)},
+			from:       day(2026, time.March, 2),
+			to:         day(2026, time.March, 3),
+			wantStarts: []time.Time{day(2026, time.March, 2), day(2026, time.March, 16), day(2026, time.March, 30)},
+		},
+		{
+			name:   "monthly skips months without the day",
+			series: domain.BookingSeries{Freq: domain.RecurrenceMonthly, Interval: 1, Count: count(4)},
+			from:   day(2026, time.January, 31),
+			to:     day(2026, time.February, 1),
+			wantStarts: []time.Time{
+				day(2026, time.January, 31), day(2026, time.March, 31),
+				day(2026, time.May, 31), day(2026, time.July, 31),
+			},
+		},
+		{
+			name:   "monthly on the 29th skips February of a common year",
+			series: domain.BookingSeries{Freq: domain.RecurrenceMonthly, Interval: 1, Until: until(2027, time.April, 1)},
+			from:   day(2027, time.January, 29),
+			to:     day(2027, time.January, 30),
+			wantStarts: []time.Time{
+				day(2027, time.January, 29), day(2027, time.March, 29),
+			},
+		},
+		{
+			name:    "count at the cap",
+			series:  domain.BookingSeries{Freq: domain.RecurrenceWeekly, Interval: 1, Count: count(maxSeriesOccurrences)},
+			from:    day(2026, time.March, 2),
+			to:      day(2026, time.March, 3),
+			wantLen: maxSeriesOccurrences,
+		},
+		{
+			name:    "count above the cap",
+			series:  domain.BookingSeries{Freq: domain.RecurrenceWeekly, Interval: 1, Count: count(maxSeriesOccurrences + 1)},
+			from:    day(2026, time.March, 2),
+			to:      day(2026, time.March, 3),
+			wantErr: ErrTooManyOccurrences,
+		},
+		{
+			name:    "until beyond the cap",
+			series:  domain.BookingSeries{Freq: domain.RecurrenceWeekly, Interval: 1, Until: until(2036, time.March, 2)},
+			from:    day(2026, time.March, 2),
+			to:      day(2026, time.March, 3),
+			wantErr: ErrTooManyOccurrences,
+		},
+		{
+			name:    "neither count nor until",
+			series:  domain.BookingSeries{Freq: domain.RecurrenceWeekly, Interval: 1},
+			from:    day(2026, time.March, 2),
+			to:      day(2026, time.March, 3),
+			wantErr: ErrInvalidRecurrence,
+		},
+		{
+			name:    "occurrences would overlap",
+			series:  domain.BookingSeries{Freq: domain.RecurrenceWeekly, Interval: 1, Count: count(2)},
+			from:    day(2026, time.March, 2),
+			to:      day(2026, time.March, 10),
+			wantErr: ErrInvalidRecurrence,
+		},
+		{
+			name:    "until before the first occurrence",
+			series:  domain.BookingSeries{Freq: domain.RecurrenceWeekly, Interval: 1, Until: until(2026, time.March, 1)},
+			from:    day(2026, time.March, 2),
+			to:      day(2026, time.March, 3),
+			wantErr: ErrInvalidRecurrence,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := expandRecurrence(&tt.series, tt.from, tt.to)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expandRecurrence: %v", err)
+			}
+
+			duration := tt.to.Sub(tt.from)
+			starts := make([]time.Time, len(res))
+			for i, o := range res {
+				starts[i] = o.from
+				if o.to.Sub(o.from) != duration {
+					t.Errorf("occurrence %d lasts %s, want %s", i, o.to.Sub(o.from), duration)
+				}
+			}
+			if tt.wantStarts != nil && !slices.Equal(starts, tt.wantStarts) {
+				t.Errorf("starts = %v, want %v", starts, tt.wantStarts)
+			}
+			if tt.wantLen != 0 && len(res) != tt.wantLen {
+				t.Errorf("got %d occurrences, want %d", len(res), tt.wantLen)
+			}
+		})
+	}
+}
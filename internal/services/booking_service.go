@@ -5,45 +5,74 @@ import (
 	"time"
 
 	"SpaceBookProject/internal/domain"
+	"SpaceBookProject/internal/pricing"
 	"SpaceBookProject/internal/repository"
 )
 
 var (
 	ErrForbidden          = errors.New("forbidden")
 	ErrAlreadyStarted     = errors.New("booking already started")
-	ErrWrongStatus        = errors.New("invalid booking status")
-	ErrOverlappingBooking = errors.New("overlapping approved booking")
+	ErrAlreadyFinished    = errors.New("booking already finished")
+	ErrWrongStatus        = repository.ErrInvalidStatusTransition
+	ErrOverlappingBooking = repository.ErrOverlappingBooking
+	ErrSpaceInactive      = errors.New("space is archived")
+	ErrInvalidDateRange   = errors.New("date_from must be before date_to")
+	ErrDateRangeTooLong   = errors.New("date range is too long")
+	ErrInvalidPricingRule = errors.New("pricing rule must set either price or adjust_pct and valid dates")
+	ErrInvalidBookingTime = errors.New("use YYYY-MM-DD dates for daily spaces and RFC3339 times for hourly spaces")
+	ErrSlotTooShort       = errors.New("booking is shorter than the minimum slot of the space")
+	ErrInvalidRecurrence  = errors.New("invalid recurrence rule")
+	ErrTooManyOccurrences = errors.New("too many occurrences in booking series")
+	ErrSeriesConflict     = errors.New("some occurrences overlap with approved bookings")
+	ErrSameDates          = errors.New("new dates are the same as the current ones")
+	ErrNotStarted         = errors.New("booking has not started yet")
+	ErrNotCheckedIn       = errors.New("guest has not checked in")
+	ErrAlreadyCheckedIn   = errors.New("guest has already checked in")
+
+	ErrReasonRequired            = errors.New("reason is required")
+	ErrEmptyMessage              = errors.New("message body is empty")
+	ErrInvalidLocation           = errors.New("latitude and longitude must be set together")
+	ErrInvalidSearchFilter       = errors.New("invalid search filter: near needs valid lat/lng and radius_km up to 500, available_from and available_to go together")
+	ErrInvalidCancellationPolicy = errors.New("custom cancellation policy needs tiers with unique days_before and refund_pct between 0 and 100")
 )
 
 type BookingService struct {
 	bookings *repository.BookingRepository
 	spaces   *repository.SpaceRepository
-	events   chan<- domain.BookingEvent
+	rules    *repository.PricingRuleRepository
 	history  *repository.BookingHistoryRepository
 }
 
-func NewBookingService(bookings *repository.BookingRepository, spaces *repository.SpaceRepository, history *repository.BookingHistoryRepository, events chan<- domain.BookingEvent) *BookingService {
+func NewBookingService(bookings *repository.BookingRepository, spaces *repository.SpaceRepository, rules *repository.PricingRuleRepository, history *repository.BookingHistoryRepository) *BookingService {
 	return &BookingService{
 		bookings: bookings,
 		spaces:   spaces,
+		rules:    rules,
 		history:  history,
-		events:   events,
 	}
 }
 
 const dateLayout = "2006-01-02"
 
 func (s *BookingService) CreateBooking(tenantID int, req *domain.CreateBookingRequest) (*domain.Booking, error) {
-	from, err := time.Parse(dateLayout, req.DateFrom)
+	from, err := ParseBookingTime(req.DateFrom)
 	if err != nil {
 		return nil, err
 	}
-	to, err := time.Parse(dateLayout, req.DateTo)
+	to, err := ParseBookingTime(req.DateTo)
 	if err != nil {
 		return nil, err
 	}
-	if !from.Before(to) {
-		return nil, errors.New("date_from must be before date_to")
+
+	sp, err := s.spaces.GetByID(req.SpaceID)
+	if err != nil {
+		return nil, err
+	}
+	if !sp.IsActive {
+		return nil, ErrSpaceInactive
+	}
+	if err := checkPeriod(sp, from, to); err != nil {
+		return nil, err
 	}
 
 	hasOverlap, err := s.bookings.HasApprovedOverlap(req.SpaceID, from, to, nil)
@@ -54,68 +83,62 @@ func (s *BookingService) CreateBooking(tenantID int, req *domain.CreateBookingRe
 		return nil, errors.New("space is already booked for these dates")
 	}
 
+	// Цена фиксируется в брони, чтобы последующие изменения цены пространства её не меняли
+	rules, err := s.rules.ListBySpace(sp.ID)
+	if err != nil {
+		return nil, err
+	}
+	quote, err := pricing.Quote(sp, rules, from, to)
+	if err != nil {
+		return nil, err
+	}
+
 	b := &domain.Booking{
-		SpaceID:  req.SpaceID,
-		TenantID: tenantID,
-		Status:   domain.BookingStatusPending,
-		DateFrom: from,
-		DateTo:   to,
+		SpaceID:    req.SpaceID,
+		TenantID:   tenantID,
+		Status:     domain.BookingStatusPending,
+		DateFrom:   from,
+		DateTo:     to,
+		TotalPrice: &quote.Total,
+		Currency:   &quote.Currency,
 	}
+	snapshotCancellationPolicy(b, sp)
 
 	if err := s.bookings.Create(b); err != nil {
 		return nil, err
 	}
-	if s.events != nil {
-		s.events <- domain.BookingEvent{
-			Type:      domain.BookingEventCreated,
-			BookingID: b.ID,
-			SpaceID:   b.SpaceID,
-			TenantID:  b.TenantID,
-			At:        time.Now(),
-		}
-	}
 
 	return b, nil
 }
 
-func (s *BookingService) ListMyBookings(tenantID int) ([]domain.Booking, error) {
-	return s.bookings.ListByTenant(tenantID)
+func (s *BookingService) ListMyBookings(tenantID int, page repository.PageRequest) (*domain.Page[domain.Booking], error) {
+	return s.bookings.ListByTenant(tenantID, page)
 }
 
-func (s *BookingService) ListOwnerBookings(ownerID int) ([]domain.Booking, error) {
-	return s.bookings.ListByOwner(ownerID)
+func (s *BookingService) ListOwnerBookings(ownerID int, page repository.PageRequest) (*domain.Page[domain.Booking], error) {
+	return s.bookings.ListByOwner(ownerID, page)
 }
 
-func (s *BookingService) CancelBooking(id, tenantID int, reason *string) error {
+// CancelBooking отменяет бронь арендатора и сохраняет возврат по политике отмены
+func (s *BookingService) CancelBooking(id, tenantID int, reason *string) (*domain.RefundQuote, error) {
 	b, err := s.bookings.GetByID(id)
 	if err != nil {
-		return err
-	}
-
-	if b.TenantID != tenantID {
-		return ErrForbidden
-	}
-	if time.Now().After(b.DateFrom) {
-		return ErrAlreadyStarted
+		return nil, err
 	}
-	if b.Status != domain.BookingStatusPending && b.Status != domain.BookingStatusApproved {
-		return ErrWrongStatus
+	if err := checkCancellable(b, tenantID); err != nil {
+		return nil, err
 	}
 
-	if err := s.bookings.UpdateStatus(id, domain.BookingStatusCancelled, tenantID, reason); err != nil {
-		return err
+	refund, err := s.refundFor(b, time.Now())
+	if err != nil {
+		return nil, err
 	}
-	if s.events != nil {
-		s.events <- domain.BookingEvent{
-			Type:      domain.BookingEventCancelled,
-			BookingID: b.ID,
-			SpaceID:   b.SpaceID,
-			TenantID:  b.TenantID,
-			At:        time.Now(),
-		}
+	// Возврат посчитан для прочитанного статуса: если бронь успели одобрить, отмена не пройдёт
+	if err := s.bookings.Cancel(id, []domain.BookingStatus{b.Status}, &tenantID, domain.HistoryActorTenant, reason, refund); err != nil {
+		return nil, err
 	}
 
-	return nil
+	return refund, nil
 }
 
 func (s *BookingService) ApproveBooking(id int, ownerID int, reason *string) error {
@@ -145,18 +168,10 @@ func (s *BookingService) ApproveBooking(id int, ownerID int, reason *string) err
 		return ErrOverlappingBooking
 	}
 
-	if err := s.bookings.UpdateStatus(id, domain.BookingStatusApproved, ownerID, reason); err != nil {
+	// Конкурирующие заявки на те же даты отклоняются в той же транзакции
+	if _, err := s.bookings.Approve(id, ownerID, reason); err != nil {
 		return err
 	}
-	if s.events != nil {
-		s.events <- domain.BookingEvent{
-			Type:      domain.BookingEventApproved,
-			BookingID: b.ID,
-			SpaceID:   b.SpaceID,
-			TenantID:  b.TenantID,
-			At:        time.Now(),
-		}
-	}
 
 	return nil
 }
@@ -180,18 +195,9 @@ func (s *BookingService) RejectBooking(id int, ownerID int, reason *string) erro
 		return ErrWrongStatus
 	}
 
-	if err := s.bookings.UpdateStatus(id, domain.BookingStatusRejected, ownerID, reason); err != nil {
+	if err := s.bookings.UpdateStatus(id, []domain.BookingStatus{domain.BookingStatusPending}, domain.BookingStatusRejected, ownerID, domain.HistoryActorOwner, reason); err != nil {
 		return err
 	}
-	if s.events != nil {
-		s.events <- domain.BookingEvent{
-			Type:      domain.BookingEventRejected,
-			BookingID: b.ID,
-			SpaceID:   b.SpaceID,
-			TenantID:  b.TenantID,
-			At:        time.Now(),
-		}
-	}
 
 	return nil
 }
@@ -203,22 +209,52 @@ func (s *BookingService) GetBookingHistory(bookingID, userID int, userRole domai
 	if err != nil {
 		return nil, err
 	}
+	if err := checkBookingAccess(s.spaces, booking, userID, userRole); err != nil {
+		return nil, err
+	}
+
+	return s.bookings.GetStatusHistory(bookingID)
+}
 
+// checkBookingAccess пускает к брони только её арендатора и владельца пространства
+func checkBookingAccess(spaces *repository.SpaceRepository, booking *domain.Booking, userID int, userRole domain.UserRole) error {
 	// Арендатор может видеть только свои брони
 	if userRole == domain.RoleTenant && booking.TenantID != userID {
-		return nil, ErrForbidden
+		return ErrForbidden
 	}
 
 	// Владелец может видеть только брони своих пространств
 	if userRole == domain.RoleOwner {
-		space, err := s.spaces.GetByID(booking.SpaceID)
+		space, err := spaces.GetByID(booking.SpaceID)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		if space.OwnerID != userID {
-			return nil, ErrForbidden
+			return ErrForbidden
 		}
 	}
 
-	return s.bookings.GetStatusHistory(bookingID)
+	return nil
+}
+
+// ExpirePendingBookings закрывает заявки, которые ждут решения дольше pendingTTL
+// или дата заезда которых уже наступила. Возвращает количество истёкших броней.
+func (s *BookingService) ExpirePendingBookings(pendingTTL time.Duration) (int, error) {
+	expired, err := s.bookings.ExpirePending(time.Now().Add(-pendingTTL), "pending request expired")
+	if err != nil {
+		return 0, err
+	}
+	return len(expired), nil
+}
+
+// checkSpaceOwner проверяет, что пространство принадлежит владельцу
+func (s *BookingService) checkSpaceOwner(spaceID, ownerID int) error {
+	space, err := s.spaces.GetByID(spaceID)
+	if err != nil {
+		return err
+	}
+	if space.OwnerID != ownerID {
+		return ErrForbidden
+	}
+	return nil
 }
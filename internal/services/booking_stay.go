@@ -0,0 +1,98 @@
+package services
+
+import (
+	"time"
+
+	"SpaceBookProject/internal/domain"
+	"SpaceBookProject/internal/repository"
+)
+
+// CheckIn отмечает заезд арендатора. Отметить можно только одобренную бронь, период которой начался.
+func (s *BookingService) CheckIn(id, ownerID int) (*domain.Booking, error) {
+	b, err := s.getOwnedBooking(id, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if b.Status != domain.BookingStatusApproved {
+		return nil, ErrWrongStatus
+	}
+	if b.CheckedInAt != nil {
+		return nil, ErrAlreadyCheckedIn
+	}
+	if time.Now().Before(b.DateFrom) {
+		return nil, ErrNotStarted
+	}
+
+	at, err := s.bookings.CheckIn(id)
+	if err == repository.ErrBookingNotCheckable {
+		// Статус или отметка успели измениться параллельно
+		return nil, ErrWrongStatus
+	}
+	if err != nil {
+		return nil, err
+	}
+	b.CheckedInAt = &at
+
+	return b, nil
+}
+
+// CheckOut отмечает выезд и сразу завершает бронь, не дожидаясь фоновой задачи
+func (s *BookingService) CheckOut(id, ownerID int) (*domain.Booking, error) {
+	b, err := s.getOwnedBooking(id, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if b.Status != domain.BookingStatusApproved {
+		return nil, ErrWrongStatus
+	}
+	if b.CheckedInAt == nil {
+		return nil, ErrNotCheckedIn
+	}
+
+	if err := s.bookings.CheckOut(id, ownerID); err != nil {
+		return nil, err
+	}
+
+	return s.bookings.GetByID(id)
+}
+
+// MarkNoShow фиксирует неявку. Допускается и после автоматического завершения,
+// если заезд так и не был отмечен.
+func (s *BookingService) MarkNoShow(id, ownerID int, reason *string) error {
+	b, err := s.getOwnedBooking(id, ownerID)
+	if err != nil {
+		return err
+	}
+	if b.Status != domain.BookingStatusApproved && b.Status != domain.BookingStatusCompleted {
+		return ErrWrongStatus
+	}
+	if b.CheckedInAt != nil {
+		return ErrAlreadyCheckedIn
+	}
+	if time.Now().Before(b.DateFrom) {
+		return ErrNotStarted
+	}
+
+	from := []domain.BookingStatus{domain.BookingStatusApproved, domain.BookingStatusCompleted}
+	return s.bookings.UpdateStatus(id, from, domain.BookingStatusNoShow, ownerID, domain.HistoryActorOwner, reason)
+}
+
+// CompleteFinishedBookings завершает одобренные брони, период которых закончился
+func (s *BookingService) CompleteFinishedBookings() (int, error) {
+	completed, err := s.bookings.CompleteFinished("stay finished")
+	if err != nil {
+		return 0, err
+	}
+	return len(completed), nil
+}
+
+func (s *BookingService) getOwnedBooking(id, ownerID int) (*domain.Booking, error) {
+	b, err := s.bookings.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkSpaceOwner(b.SpaceID, ownerID); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"SpaceBookProject/internal/domain"
+	"SpaceBookProject/internal/pricing"
+)
+
+// normalizeCancellationPolicy проверяет политику отмены пространства.
+// Ступени задаются только для custom, у стандартных политик они не хранятся.
+func normalizeCancellationPolicy(space *domain.Space) error {
+	if space.CancellationPolicy == "" {
+		space.CancellationPolicy = domain.CancellationFlexible
+	}
+	if space.CancellationPolicy != domain.CancellationCustom {
+		space.CancellationTiers = nil
+		return nil
+	}
+
+	if len(space.CancellationTiers) == 0 {
+		return ErrInvalidCancellationPolicy
+	}
+	seen := make(map[int]bool, len(space.CancellationTiers))
+	for _, tier := range space.CancellationTiers {
+		if tier.DaysBefore < 0 || tier.RefundPct < 0 || tier.RefundPct > 100 || seen[tier.DaysBefore] {
+			return ErrInvalidCancellationPolicy
+		}
+		seen[tier.DaysBefore] = true
+	}
+	return nil
+}
+
+// snapshotCancellationPolicy фиксирует в брони текущую политику отмены пространства
+func snapshotCancellationPolicy(b *domain.Booking, sp *domain.Space) {
+	policy := sp.CancellationPolicy
+	b.CancellationPolicy = &policy
+	b.CancellationTiers = pricing.PolicyTiers(sp.CancellationPolicy, sp.CancellationTiers)
+}
+
+// PreviewCancellation показывает арендатору, сколько вернётся при отмене брони прямо сейчас
+func (s *BookingService) PreviewCancellation(id, tenantID int) (*domain.RefundQuote, error) {
+	b, err := s.bookings.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCancellable(b, tenantID); err != nil {
+		return nil, err
+	}
+	return s.refundFor(b, time.Now())
+}
+
+// OwnerCancelBooking отменяет одобренную бронь по инициативе владельца, например при аварии в помещении.
+// Отменить можно и во время проживания, до даты выезда: тогда возвращается стоимость оставшегося
+// времени (pricing.OwnerRefundPct), до заезда — полная. Причина обязательна, возврат пишется в историю.
+func (s *BookingService) OwnerCancelBooking(id, ownerID int, reason string) (*domain.RefundQuote, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrReasonRequired
+	}
+
+	b, err := s.bookings.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkSpaceOwner(b.SpaceID, ownerID); err != nil {
+		return nil, err
+	}
+	if b.Status != domain.BookingStatusApproved {
+		return nil, ErrWrongStatus
+	}
+	now := time.Now()
+	if !now.Before(b.DateTo) {
+		return nil, ErrAlreadyFinished
+	}
+
+	refund, err := s.refundFor(b, now)
+	if err != nil {
+		return nil, err
+	}
+	refund.RefundPct = pricing.OwnerRefundPct(b, now)
+	refund.RefundAmount = nil
+	if b.TotalPrice != nil {
+		amount := *b.TotalPrice * refund.RefundPct / 100
+		refund.RefundAmount = &amount
+	}
+
+	if err := s.bookings.Cancel(id, []domain.BookingStatus{domain.BookingStatusApproved}, &ownerID, domain.HistoryActorOwner, &reason, refund); err != nil {
+		return nil, err
+	}
+
+	return refund, nil
+}
+
+func checkCancellable(b *domain.Booking, tenantID int) error {
+	if b.TenantID != tenantID {
+		return ErrForbidden
+	}
+	if time.Now().After(b.DateFrom) {
+		return ErrAlreadyStarted
+	}
+	if b.Status != domain.BookingStatusPending && b.Status != domain.BookingStatusApproved {
+		return ErrWrongStatus
+	}
+	return nil
+}
+
+// refundFor считает возврат по политике, зафиксированной в брони.
+// Для броней, созданных до появления политик, берётся текущая политика пространства.
+func (s *BookingService) refundFor(b *domain.Booking, now time.Time) (*domain.RefundQuote, error) {
+	if b.CancellationPolicy != nil {
+		return pricing.Refund(b, *b.CancellationPolicy, b.CancellationTiers, now), nil
+	}
+
+	sp, err := s.spaces.GetByID(b.SpaceID)
+	if err != nil {
+		return nil, err
+	}
+	tiers := pricing.PolicyTiers(sp.CancellationPolicy, sp.CancellationTiers)
+	return pricing.Refund(b, sp.CancellationPolicy, tiers, now), nil
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"SpaceBookProject/internal/domain"
+)
+
+func TestNormalizeCancellationPolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     domain.CancellationPolicy
+		tiers      []domain.CancellationTier
+		wantPolicy domain.CancellationPolicy
+		wantTiers  int
+		wantErr    error
+	}{
+		{name: "empty defaults to flexible", wantPolicy: domain.CancellationFlexible},
+		{
+			name:       "preset drops tiers",
+			policy:     domain.CancellationStrict,
+			tiers:      []domain.CancellationTier{{DaysBefore: 3, RefundPct: 100}},
+			wantPolicy: domain.CancellationStrict,
+		},
+		{
+			name:       "custom tiers are kept",
+			policy:     domain.CancellationCustom,
+			tiers:      []domain.CancellationTier{{DaysBefore: 0, RefundPct: 0}, {DaysBefore: 10, RefundPct: 100}},
+			wantPolicy: domain.CancellationCustom,
+			wantTiers:  2,
+		},
+		{name: "custom without tiers", policy: domain.CancellationCustom, wantErr: ErrInvalidCancellationPolicy},
+		{
+			name:    "negative days",
+			policy:  domain.CancellationCustom,
+			tiers:   []domain.CancellationTier{{DaysBefore: -1, RefundPct: 50}},
+			wantErr: ErrInvalidCancellationPolicy,
+		},
+		{
+			name:    "refund above 100",
+			policy:  domain.CancellationCustom,
+			tiers:   []domain.CancellationTier{{DaysBefore: 1, RefundPct: 101}},
+			wantErr: ErrInvalidCancellationPolicy,
+		},
+		{
+			name:    "negative refund",
+			policy:  domain.CancellationCustom,
+			tiers:   []domain.CancellationTier{{DaysBefore: 1, RefundPct: -1}},
+			wantErr: ErrInvalidCancellationPolicy,
+		},
+		{
+			name:    "duplicate days",
+			policy:  domain.CancellationCustom,
+			tiers:   []domain.CancellationTier{{DaysBefore: 3, RefundPct: 100}, {DaysBefore: 3, RefundPct: 50}},
+			wantErr: ErrInvalidCancellationPolicy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := &domain.Space{CancellationPolicy: tt.policy, CancellationTiers: tt.tiers}
+			err := normalizeCancellationPolicy(sp)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if sp.CancellationPolicy != tt.wantPolicy {
+				t.Errorf("policy = %s, want %s", sp.CancellationPolicy, tt.wantPolicy)
+			}
+			if len(sp.CancellationTiers) != tt.wantTiers {
+				t.Errorf("got %d tiers, want %d", len(sp.CancellationTiers), tt.wantTiers)
+			}
+		})
+	}
+}
@@ -2,17 +2,20 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 
 	"SpaceBookProject/internal/domain"
 	"SpaceBookProject/internal/repository"
 )
 
 type FavoritesService struct {
-	repo *repository.FavoritesRepository
+	repo   *repository.FavoritesRepository
+	spaces *SpaceService
 }
 
-func NewFavoritesService(repo *repository.FavoritesRepository) *FavoritesService {
-	return &FavoritesService{repo: repo}
+func NewFavoritesService(repo *repository.FavoritesRepository, spaces *SpaceService) *FavoritesService {
+	return &FavoritesService{repo: repo, spaces: spaces}
 }
 
 func (s *FavoritesService) Add(ctx context.Context, userID, spaceID int) error {
@@ -23,6 +26,142 @@ func (s *FavoritesService) Remove(ctx context.Context, userID, spaceID int) erro
 	return s.repo.Remove(ctx, userID, spaceID)
 }
 
-func (s *FavoritesService) List(ctx context.Context, userID int) ([]domain.Space, error) {
-	return s.repo.List(ctx, userID)
+func (s *FavoritesService) List(ctx context.Context, userID int, page repository.PageRequest) (*domain.Page[domain.Space], error) {
+	result, err := s.repo.List(ctx, userID, page)
+	if err != nil {
+		return nil, err
+	}
+	return result, s.spaces.attachDetails(result.Items)
+}
+
+func (s *FavoritesService) ListCollections(ctx context.Context, userID int) ([]domain.Collection, error) {
+	return s.repo.ListCollections(ctx, userID)
+}
+
+func (s *FavoritesService) CreateCollection(ctx context.Context, userID int, name string) (*domain.Collection, error) {
+	c := &domain.Collection{UserID: userID, Name: name}
+	if err := s.repo.CreateCollection(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// GetCollection возвращает подборку пользователя вместе с пространствами
+func (s *FavoritesService) GetCollection(ctx context.Context, id, userID int) (*domain.Collection, error) {
+	c, err := s.getOwnedCollection(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	return c, s.loadCollectionSpaces(ctx, c, false)
+}
+
+// GetSharedCollection открывает подборку по ссылке; архивные пространства не показываются
+func (s *FavoritesService) GetSharedCollection(ctx context.Context, token string) (*domain.SharedCollection, error) {
+	c, err := s.repo.GetCollectionByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loadCollectionSpaces(ctx, c, true); err != nil {
+		return nil, err
+	}
+	spaces := c.Spaces
+	if spaces == nil {
+		spaces = []domain.Space{}
+	}
+	return &domain.SharedCollection{
+		Name:       c.Name,
+		SpaceCount: len(spaces),
+		Spaces:     spaces,
+		UpdatedAt:  c.UpdatedAt,
+	}, nil
+}
+
+func (s *FavoritesService) RenameCollection(ctx context.Context, id, userID int, name string) (*domain.Collection, error) {
+	if _, err := s.getOwnedCollection(ctx, id, userID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.RenameCollection(ctx, id, name); err != nil {
+		return nil, err
+	}
+	return s.repo.GetCollection(ctx, id)
+}
+
+func (s *FavoritesService) DeleteCollection(ctx context.Context, id, userID int) error {
+	if _, err := s.getOwnedCollection(ctx, id, userID); err != nil {
+		return err
+	}
+	return s.repo.DeleteCollection(ctx, id)
+}
+
+func (s *FavoritesService) AddToCollection(ctx context.Context, id, userID int, spaceIDs []int) (*domain.Collection, error) {
+	if _, err := s.getOwnedCollection(ctx, id, userID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.AddToCollection(ctx, id, spaceIDs); err != nil {
+		return nil, err
+	}
+	return s.GetCollection(ctx, id, userID)
+}
+
+func (s *FavoritesService) RemoveFromCollection(ctx context.Context, id, userID, spaceID int) error {
+	if _, err := s.getOwnedCollection(ctx, id, userID); err != nil {
+		return err
+	}
+	return s.repo.RemoveFromCollection(ctx, id, spaceID)
+}
+
+// ShareCollection выдаёт ссылку только для чтения; повторный вызов возвращает ту же ссылку
+func (s *FavoritesService) ShareCollection(ctx context.Context, id, userID int) (*domain.Collection, error) {
+	c, err := s.getOwnedCollection(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if c.ShareToken != nil {
+		return c, nil
+	}
+
+	token, err := newShareToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetShareToken(ctx, id, &token); err != nil {
+		return nil, err
+	}
+	return s.repo.GetCollection(ctx, id)
+}
+
+// UnshareCollection закрывает доступ по ссылке; старый токен перестаёт работать
+func (s *FavoritesService) UnshareCollection(ctx context.Context, id, userID int) error {
+	if _, err := s.getOwnedCollection(ctx, id, userID); err != nil {
+		return err
+	}
+	return s.repo.SetShareToken(ctx, id, nil)
+}
+
+func (s *FavoritesService) getOwnedCollection(ctx context.Context, id, userID int) (*domain.Collection, error) {
+	c, err := s.repo.GetCollection(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.UserID != userID {
+		return nil, ErrForbidden
+	}
+	return c, nil
+}
+
+func (s *FavoritesService) loadCollectionSpaces(ctx context.Context, c *domain.Collection, onlyActive bool) error {
+	spaces, err := s.repo.ListCollectionSpaces(ctx, c.ID, onlyActive)
+	if err != nil {
+		return err
+	}
+	c.Spaces = spaces
+	return s.spaces.attachDetails(c.Spaces)
+}
+
+func newShareToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
 }
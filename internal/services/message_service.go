@@ -0,0 +1,127 @@
+package services
+
+import (
+	"strings"
+
+	"SpaceBookProject/internal/domain"
+	"SpaceBookProject/internal/repository"
+)
+
+type MessageService struct {
+	messages *repository.MessageRepository
+	bookings *repository.BookingRepository
+	spaces   *repository.SpaceRepository
+}
+
+func NewMessageService(
+	messages *repository.MessageRepository,
+	bookings *repository.BookingRepository,
+	spaces *repository.SpaceRepository,
+) *MessageService {
+	return &MessageService{
+		messages: messages,
+		bookings: bookings,
+		spaces:   spaces,
+	}
+}
+
+// ListConversations возвращает все переписки пользователя с числом непрочитанных
+func (s *MessageService) ListConversations(userID int) ([]domain.Conversation, error) {
+	return s.messages.ListConversations(userID)
+}
+
+// BookingMessages открывает переписку по брони; доступ как к истории брони
+func (s *MessageService) BookingMessages(bookingID, userID int, role domain.UserRole) ([]domain.Message, error) {
+	c, err := s.bookingConversation(bookingID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+	return s.messages.ReadMessages(c.ID, userID)
+}
+
+func (s *MessageService) SendBookingMessage(bookingID, userID int, role domain.UserRole, body string) (*domain.Message, error) {
+	c, err := s.bookingConversation(bookingID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+	return s.send(c, userID, body)
+}
+
+// CreateInquiry — вопрос арендатора по пространству до бронирования.
+// Повторные вопросы по тому же пространству попадают в ту же переписку.
+func (s *MessageService) CreateInquiry(tenantID int, req *domain.CreateInquiryRequest) (*domain.Message, error) {
+	sp, err := s.spaces.GetByID(req.SpaceID)
+	if err != nil {
+		return nil, err
+	}
+	if !sp.IsActive {
+		return nil, ErrSpaceInactive
+	}
+
+	c, err := s.messages.InquiryConversation(sp.ID, tenantID, sp.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	return s.send(c, tenantID, req.Body)
+}
+
+func (s *MessageService) ConversationMessages(conversationID, userID int) ([]domain.Message, error) {
+	c, err := s.participantConversation(conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.messages.ReadMessages(c.ID, userID)
+}
+
+func (s *MessageService) SendMessage(conversationID, userID int, body string) (*domain.Message, error) {
+	c, err := s.participantConversation(conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.send(c, userID, body)
+}
+
+func (s *MessageService) bookingConversation(bookingID, userID int, role domain.UserRole) (*domain.Conversation, error) {
+	b, err := s.bookings.GetByID(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBookingAccess(s.spaces, b, userID, role); err != nil {
+		return nil, err
+	}
+
+	sp, err := s.spaces.GetByID(b.SpaceID)
+	if err != nil {
+		return nil, err
+	}
+	return s.messages.BookingConversation(b, sp.OwnerID)
+}
+
+func (s *MessageService) participantConversation(conversationID, userID int) (*domain.Conversation, error) {
+	c, err := s.messages.GetConversation(conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if c.TenantID != userID && c.OwnerID != userID {
+		return nil, ErrForbidden
+	}
+	return c, nil
+}
+
+func (s *MessageService) send(c *domain.Conversation, senderID int, body string) (*domain.Message, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, ErrEmptyMessage
+	}
+
+	m := &domain.Message{
+		ConversationID: c.ID,
+		SenderID:       senderID,
+		Body:           body,
+	}
+	if err := s.messages.AddMessage(m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+
+	"SpaceBookProject/internal/domain"
+	"SpaceBookProject/internal/repository"
+)
+
+// PriceAlertService сообщает пользователям о снижении цены пространств из избранного
+type PriceAlertService struct {
+	favorites *repository.FavoritesRepository
+}
+
+func NewPriceAlertService(favorites *repository.FavoritesRepository) *PriceAlertService {
+	return &PriceAlertService{favorites: favorites}
+}
+
+func (s *PriceAlertService) Settings(ctx context.Context, userID int) (*domain.PriceAlertSettings, error) {
+	return s.favorites.GetPriceAlertSettings(ctx, userID)
+}
+
+func (s *PriceAlertService) UpdateSettings(ctx context.Context, userID int, req *domain.UpdatePriceAlertSettingsRequest) (*domain.PriceAlertSettings, error) {
+	settings, err := s.favorites.GetPriceAlertSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Enabled != nil {
+		settings.Enabled = *req.Enabled
+	}
+	if req.ThresholdPct != nil {
+		settings.ThresholdPct = *req.ThresholdPct
+	}
+
+	if err := s.favorites.SavePriceAlertSettings(ctx, userID, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// SpacePriceChanged вызывается после сохранения новой цены пространства.
+// При смене валюты сравнивать цены нельзя, поэтому точка отсчёта просто сбрасывается.
+func (s *PriceAlertService) SpacePriceChanged(ctx context.Context, space *domain.Space, oldCurrency string) error {
+	if space.Currency != oldCurrency {
+		return s.favorites.ResetPriceBaseline(ctx, space.ID, space.Price)
+	}
+
+	_, err := s.favorites.ClaimPriceDrops(ctx, space.ID, space.Price)
+	return err
+}
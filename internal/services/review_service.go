@@ -0,0 +1,161 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"SpaceBookProject/internal/domain"
+	"SpaceBookProject/internal/repository"
+)
+
+var (
+	ErrReviewWindowClosed = errors.New("review window for this booking is closed")
+	ErrNotCompleted       = errors.New("only completed bookings can be reviewed")
+	ErrEmptyReply         = errors.New("reply is empty")
+)
+
+type ReviewService struct {
+	reviews  *repository.ReviewRepository
+	bookings *repository.BookingRepository
+	spaces   *repository.SpaceRepository
+	users    *repository.UserRepository
+	window   time.Duration
+}
+
+// window — сколько времени после выезда стороны могут оставить отзыв
+func NewReviewService(
+	reviews *repository.ReviewRepository,
+	bookings *repository.BookingRepository,
+	spaces *repository.SpaceRepository,
+	users *repository.UserRepository,
+	window time.Duration,
+) *ReviewService {
+	return &ReviewService{
+		reviews:  reviews,
+		bookings: bookings,
+		spaces:   spaces,
+		users:    users,
+		window:   window,
+	}
+}
+
+// ReviewSpace — отзыв арендатора о пространстве по его завершённой брони
+func (s *ReviewService) ReviewSpace(bookingID, tenantID int, req *domain.CreateReviewRequest) (*domain.Review, error) {
+	b, err := s.reviewableBooking(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if b.TenantID != tenantID {
+		return nil, ErrForbidden
+	}
+	return s.create(b, domain.ReviewOfSpace, tenantID, req)
+}
+
+// ReviewTenant — отзыв владельца об арендаторе по завершённой брони в его пространстве
+func (s *ReviewService) ReviewTenant(bookingID, ownerID int, req *domain.CreateReviewRequest) (*domain.Review, error) {
+	b, err := s.reviewableBooking(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	sp, err := s.spaces.GetByID(b.SpaceID)
+	if err != nil {
+		return nil, err
+	}
+	if sp.OwnerID != ownerID {
+		return nil, ErrForbidden
+	}
+	return s.create(b, domain.ReviewOfTenant, ownerID, req)
+}
+
+func (s *ReviewService) create(b *domain.Booking, direction domain.ReviewDirection, authorID int, req *domain.CreateReviewRequest) (*domain.Review, error) {
+	rv := &domain.Review{
+		BookingID: b.ID,
+		Direction: direction,
+		AuthorID:  authorID,
+		SpaceID:   b.SpaceID,
+		TenantID:  b.TenantID,
+		Rating:    req.Rating,
+		Comment:   strings.TrimSpace(req.Comment),
+	}
+	if err := s.reviews.Create(rv); err != nil {
+		return nil, err
+	}
+	return rv, nil
+}
+
+// reviewableBooking проверяет, что бронь завершена и окно для отзывов ещё открыто.
+// Окно отсчитывается от фактического выезда, а если он не отмечен — от конца брони.
+func (s *ReviewService) reviewableBooking(bookingID int) (*domain.Booking, error) {
+	b, err := s.bookings.GetByID(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if b.Status != domain.BookingStatusCompleted {
+		return nil, ErrNotCompleted
+	}
+
+	end := b.DateTo
+	if b.CheckedOutAt != nil {
+		end = *b.CheckedOutAt
+	}
+	if time.Now().After(end.Add(s.window)) {
+		return nil, ErrReviewWindowClosed
+	}
+	return b, nil
+}
+
+// Reply — публичный ответ владельца на отзыв о его пространстве
+func (s *ReviewService) Reply(reviewID, ownerID int, reply string) (*domain.Review, error) {
+	// Ответ один, поэтому пробельный ответ не должен его занимать
+	reply = strings.TrimSpace(reply)
+	if reply == "" {
+		return nil, ErrEmptyReply
+	}
+
+	rv, err := s.reviews.GetByID(reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if rv.Direction != domain.ReviewOfSpace {
+		return nil, ErrForbidden
+	}
+	sp, err := s.spaces.GetByID(rv.SpaceID)
+	if err != nil {
+		return nil, err
+	}
+	if sp.OwnerID != ownerID {
+		return nil, ErrForbidden
+	}
+
+	return s.reviews.SetReply(reviewID, reply)
+}
+
+func (s *ReviewService) ListSpaceReviews(spaceID int) ([]domain.Review, error) {
+	if _, err := s.spaces.GetByID(spaceID); err != nil {
+		return nil, err
+	}
+	return s.reviews.ListBySpace(spaceID)
+}
+
+func (s *ReviewService) ListUserReviews(userID int) ([]domain.Review, error) {
+	if _, err := s.users.GetByID(userID); err != nil {
+		return nil, err
+	}
+	return s.reviews.ListByTenant(userID)
+}
+
+func (s *ReviewService) GetProfile(userID int) (*domain.UserProfile, error) {
+	u, err := s.users.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.UserProfile{
+		ID:          u.ID,
+		FirstName:   u.FirstName,
+		Role:        u.Role,
+		RatingAvg:   u.RatingAvg,
+		RatingCount: u.RatingCount,
+		CreatedAt:   u.CreatedAt,
+	}, nil
+}
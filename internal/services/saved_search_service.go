@@ -0,0 +1,184 @@
+package services
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"SpaceBookProject/internal/domain"
+	"SpaceBookProject/internal/geo"
+	"SpaceBookProject/internal/repository"
+)
+
+// Радиус поиска рядом с точкой по умолчанию и максимальный, км
+const (
+	DefaultRadiusKm = 10
+	MaxRadiusKm     = 500
+)
+
+// Новые пространства ищутся с запасом: пространство, чья транзакция закоммитилась
+// уже после прошлой проверки, могло получить listed_at чуть раньше неё.
+// Повторных уведомлений не будет — совпадения запоминаются.
+const savedSearchOverlap = time.Minute
+
+type SavedSearchService struct {
+	searches *repository.SavedSearchRepository
+	spaces   *repository.SpaceRepository
+}
+
+func NewSavedSearchService(searches *repository.SavedSearchRepository, spaces *repository.SpaceRepository) *SavedSearchService {
+	return &SavedSearchService{
+		searches: searches,
+		spaces:   spaces,
+	}
+}
+
+func (s *SavedSearchService) Create(userID int, req *domain.CreateSavedSearchRequest) (*domain.SavedSearch, error) {
+	req.Filter.Amenities = NormalizeAmenityCodes(req.Filter.Amenities)
+	if _, err := SpaceFilterFrom(req.Filter); err != nil {
+		return nil, err
+	}
+
+	search := &domain.SavedSearch{UserID: userID, Name: req.Name, Filter: req.Filter}
+	if err := s.searches.Create(search); err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
+func (s *SavedSearchService) List(userID int) ([]domain.SavedSearch, error) {
+	return s.searches.ListByUser(userID)
+}
+
+func (s *SavedSearchService) SetPaused(id, userID int, paused bool) (*domain.SavedSearch, error) {
+	if _, err := s.getOwned(id, userID); err != nil {
+		return nil, err
+	}
+	if err := s.searches.SetPaused(id, paused); err != nil {
+		return nil, err
+	}
+	return s.searches.GetByID(id)
+}
+
+func (s *SavedSearchService) Delete(id, userID int) error {
+	if _, err := s.getOwned(id, userID); err != nil {
+		return err
+	}
+	return s.searches.Delete(id)
+}
+
+func (s *SavedSearchService) getOwned(id, userID int) (*domain.SavedSearch, error) {
+	search, err := s.searches.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if search.UserID != userID {
+		return nil, ErrForbidden
+	}
+	return search, nil
+}
+
+// MatchNewSpaces проверяет активные поиски на пространствах, появившихся
+// в каталоге после прошлой проверки, и ставит уведомления в очередь. Возвращает число уведомлений.
+func (s *SavedSearchService) MatchNewSpaces() (int, error) {
+	searches, err := s.searches.ListActive()
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := range searches {
+		n, err := s.matchSearch(&searches[i])
+		if err != nil {
+			// Ошибка одного поиска (например, устаревший фильтр) не мешает остальным
+			log.Printf("[saved-search] search_id=%d: %v\n", searches[i].ID, err)
+			continue
+		}
+		sent += n
+	}
+	return sent, nil
+}
+
+func (s *SavedSearchService) matchSearch(search *domain.SavedSearch) (int, error) {
+	checkedAt := time.Now()
+
+	f, err := SpaceFilterFrom(search.Filter)
+	if err != nil {
+		return 0, err
+	}
+	since := search.CheckedAt.Add(-savedSearchOverlap)
+	f.ListedAfter = &since
+	f.Sort = repository.SpaceSortNewest
+
+	var (
+		ids  []int
+		page = repository.PageRequest{Limit: repository.MaxPageLimit}
+	)
+	for {
+		result, err := s.spaces.ListFiltered(f, page)
+		if err != nil {
+			return 0, err
+		}
+		for _, sp := range result.Items {
+			// О своих пространствах владельцу не сообщаем
+			if sp.OwnerID != search.UserID {
+				ids = append(ids, sp.ID)
+			}
+		}
+		if result.NextCursor == nil {
+			break
+		}
+		page.Cursor = *result.NextCursor
+	}
+
+	// Уведомления ставятся в outbox в той же транзакции, что и совпадения
+	newIDs, err := s.searches.AddMatches(search, ids, checkedAt)
+	if err != nil {
+		return 0, err
+	}
+	return len(newIDs), nil
+}
+
+// SpaceFilterFrom переводит сохранённый фильтр в фильтр каталога и проверяет его
+func SpaceFilterFrom(sf domain.SpaceSearchFilter) (repository.SpaceFilter, error) {
+	f := repository.SpaceFilter{
+		MinPrice:  sf.MinPrice,
+		MaxPrice:  sf.MaxPrice,
+		MinArea:   sf.MinArea,
+		MaxArea:   sf.MaxArea,
+		Amenities: sf.Amenities,
+	}
+	if q := strings.TrimSpace(sf.Query); q != "" {
+		f.Query = &q
+	}
+
+	if sf.Near != nil {
+		p := geo.Point{Lat: sf.Near.Lat, Lng: sf.Near.Lng}
+		if !p.Valid() {
+			return f, ErrInvalidSearchFilter
+		}
+		f.Near = &p
+		f.RadiusKm = DefaultRadiusKm
+		if sf.RadiusKm != nil {
+			if *sf.RadiusKm <= 0 || *sf.RadiusKm > MaxRadiusKm {
+				return f, ErrInvalidSearchFilter
+			}
+			f.RadiusKm = *sf.RadiusKm
+		}
+	} else if sf.RadiusKm != nil {
+		return f, ErrInvalidSearchFilter
+	}
+
+	if (sf.AvailableFrom == nil) != (sf.AvailableTo == nil) {
+		return f, ErrInvalidSearchFilter
+	}
+	if sf.AvailableFrom != nil {
+		from, errFrom := ParseBookingTime(*sf.AvailableFrom)
+		to, errTo := ParseBookingTime(*sf.AvailableTo)
+		if errFrom != nil || errTo != nil || !from.Before(to) {
+			return f, ErrInvalidSearchFilter
+		}
+		f.AvailableFrom, f.AvailableTo = &from, &to
+	}
+	return f, nil
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"log"
+	"net/http"
+
+	"SpaceBookProject/internal/domain"
+	"SpaceBookProject/internal/imaging"
+	"SpaceBookProject/internal/repository"
+)
+
+const (
+	maxPhotosPerSpace = 20
+	thumbnailSide     = 400
+	// Декодированное изображение занимает ширина×высота×4 байт, поэтому
+	// маленький сжатый PNG с огромными размерами отсекаем до декодирования
+	maxPhotoPixels = 40_000_000
+)
+
+var (
+	ErrPhotoTooLarge        = errors.New("photo is too large")
+	ErrPhotoTooManyPixels   = errors.New("photo dimensions are too large")
+	ErrUnsupportedPhotoType = errors.New("photo must be a JPEG or PNG image")
+	ErrTooManyPhotos        = repository.ErrTooManyPhotos
+)
+
+// Допустимые типы фото и расширения файлов для них
+var photoExtensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+}
+
+// UploadPhoto проверяет и сохраняет фото пространства вместе с миниатюрой.
+// Тип определяется по содержимому файла, а не по заголовку запроса.
+func (s *SpaceService) UploadPhoto(ctx context.Context, spaceID, ownerID int, data []byte) (*domain.SpacePhoto, error) {
+	if _, err := s.getOwned(spaceID, ownerID); err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.maxPhotoBytes {
+		return nil, ErrPhotoTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	ext, ok := photoExtensions[contentType]
+	if !ok {
+		return nil, ErrUnsupportedPhotoType
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedPhotoType
+	}
+	if cfg.Width*cfg.Height > maxPhotoPixels {
+		return nil, ErrPhotoTooManyPixels
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedPhotoType
+	}
+
+	// Ранняя проверка, чтобы не загружать файлы зря; окончательная — в транзакции при вставке
+	count, err := s.photos.CountBySpace(spaceID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxPhotosPerSpace {
+		return nil, ErrTooManyPhotos
+	}
+
+	thumb, err := imaging.Thumbnail(img, thumbnailSide)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := randomName()
+	if err != nil {
+		return nil, err
+	}
+	bounds := img.Bounds()
+	photo := &domain.SpacePhoto{
+		SpaceID:     spaceID,
+		StorageKey:  fmt.Sprintf("spaces/%d/%s.%s", spaceID, name, ext),
+		ThumbKey:    fmt.Sprintf("spaces/%d/%s_thumb.jpg", spaceID, name),
+		ContentType: contentType,
+		SizeBytes:   int64(len(data)),
+		Width:       bounds.Dx(),
+		Height:      bounds.Dy(),
+	}
+
+	if err := s.store.Put(ctx, photo.StorageKey, data, contentType); err != nil {
+		return nil, err
+	}
+	if err := s.store.Put(ctx, photo.ThumbKey, thumb, "image/jpeg"); err != nil {
+		s.removePhotoFiles(ctx, photo)
+		return nil, err
+	}
+	if err := s.photos.Create(photo, maxPhotosPerSpace); err != nil {
+		s.removePhotoFiles(ctx, photo)
+		return nil, err
+	}
+
+	s.setURLs(photo)
+	return photo, nil
+}
+
+func (s *SpaceService) ListPhotos(spaceID int) ([]domain.SpacePhoto, error) {
+	if _, err := s.repo.GetByID(spaceID); err != nil {
+		return nil, err
+	}
+	photos, err := s.photos.ListBySpace(spaceID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range photos {
+		s.setURLs(&photos[i])
+	}
+	return photos, nil
+}
+
+func (s *SpaceService) DeletePhoto(ctx context.Context, spaceID, photoID, ownerID int) error {
+	if _, err := s.getOwned(spaceID, ownerID); err != nil {
+		return err
+	}
+	photo, err := s.photos.Delete(photoID, spaceID)
+	if err != nil {
+		return err
+	}
+	s.removePhotoFiles(ctx, photo)
+	return nil
+}
+
+func (s *SpaceService) SetCoverPhoto(spaceID, photoID, ownerID int) ([]domain.SpacePhoto, error) {
+	if _, err := s.getOwned(spaceID, ownerID); err != nil {
+		return nil, err
+	}
+	if err := s.photos.SetCover(photoID, spaceID); err != nil {
+		return nil, err
+	}
+	return s.ListPhotos(spaceID)
+}
+
+func (s *SpaceService) ReorderPhotos(spaceID, ownerID int, photoIDs []int) ([]domain.SpacePhoto, error) {
+	if _, err := s.getOwned(spaceID, ownerID); err != nil {
+		return nil, err
+	}
+	if err := s.photos.Reorder(spaceID, photoIDs); err != nil {
+		return nil, err
+	}
+	return s.ListPhotos(spaceID)
+}
+
+// attachPhotos загружает фото для списка пространств одним запросом
+func (s *SpaceService) attachPhotos(spaces []domain.Space) error {
+	ids := make([]int, len(spaces))
+	for i := range spaces {
+		ids[i] = spaces[i].ID
+	}
+	byspace, err := s.photos.ListBySpaces(ids)
+	if err != nil {
+		return err
+	}
+	for i := range spaces {
+		s.setPhotos(&spaces[i], byspace[spaces[i].ID])
+	}
+	return nil
+}
+
+func (s *SpaceService) setPhotos(space *domain.Space, photos []domain.SpacePhoto) {
+	space.Photos = make([]domain.SpacePhoto, len(photos))
+	for i := range photos {
+		p := photos[i]
+		s.setURLs(&p)
+		if p.IsCover {
+			space.CoverURL = p.URL
+		}
+		space.Photos[i] = p
+	}
+}
+
+func (s *SpaceService) setURLs(p *domain.SpacePhoto) {
+	p.URL = s.store.URL(p.StorageKey)
+	p.ThumbnailURL = s.store.URL(p.ThumbKey)
+}
+
+// removePhotoFiles удаляет файлы фото; ошибки только логируются, запись в БД уже удалена
+func (s *SpaceService) removePhotoFiles(ctx context.Context, p *domain.SpacePhoto) {
+	for _, key := range []string{p.StorageKey, p.ThumbKey} {
+		if err := s.store.Delete(ctx, key); err != nil {
+			log.Printf("failed to delete photo file %s: %v\n", key, err)
+		}
+	}
+}
+
+func randomName() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// MaxPhotoBytes — максимальный размер загружаемого фото
+func (s *SpaceService) MaxPhotoBytes() int64 {
+	return s.maxPhotoBytes
+}
@@ -1,34 +1,368 @@
 package services
 
 import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
 	"SpaceBookProject/internal/domain"
+	"SpaceBookProject/internal/pricing"
 	"SpaceBookProject/internal/repository"
+	"SpaceBookProject/internal/storage"
 )
 
 type SpaceService struct {
-	repo *repository.SpaceRepository
+	repo          *repository.SpaceRepository
+	rules         *repository.PricingRuleRepository
+	photos        *repository.SpacePhotoRepository
+	amenities     *repository.AmenityRepository
+	priceAlerts   *PriceAlertService
+	store         storage.Storage
+	maxPhotoBytes int64
+}
+
+func NewSpaceService(
+	repo *repository.SpaceRepository,
+	rules *repository.PricingRuleRepository,
+	photos *repository.SpacePhotoRepository,
+	amenities *repository.AmenityRepository,
+	priceAlerts *PriceAlertService,
+	store storage.Storage,
+	maxPhotoBytes int64,
+) *SpaceService {
+	return &SpaceService{
+		repo:          repo,
+		rules:         rules,
+		photos:        photos,
+		amenities:     amenities,
+		priceAlerts:   priceAlerts,
+		store:         store,
+		maxPhotoBytes: maxPhotoBytes,
+	}
+}
+
+func (s *SpaceService) ListSpaces(f repository.SpaceFilter, page repository.PageRequest) (*domain.Page[domain.Space], error) {
+	result, err := s.repo.ListFiltered(f, page)
+	if err != nil {
+		return nil, err
+	}
+	return result, s.attachDetails(result.Items)
 }
 
-func NewSpaceService(repo *repository.SpaceRepository) *SpaceService {
-	return &SpaceService{repo: repo}
+func (s *SpaceService) ListOwnerSpaces(ownerID int, page repository.PageRequest) (*domain.Page[domain.Space], error) {
+	result, err := s.repo.ListByOwner(ownerID, page)
+	if err != nil {
+		return nil, err
+	}
+	return result, s.attachDetails(result.Items)
 }
 
-func (s *SpaceService) ListSpaces(f repository.SpaceFilter) ([]domain.Space, error) {
-	return s.repo.ListFiltered(f)
+// ListAmenities возвращает справочник удобств
+func (s *SpaceService) ListAmenities() ([]domain.Amenity, error) {
+	return s.amenities.List()
 }
 
 func (s *SpaceService) CreateSpace(ownerID int, req *domain.CreateSpaceRequest) (*domain.Space, error) {
 	space := &domain.Space{
-		OwnerID:     ownerID,
-		Title:       req.Title,
-		Description: req.Description,
-		AreaM2:      req.AreaM2,
-		Price:       req.Price,
-		Phone:       req.Phone,
+		OwnerID:            ownerID,
+		Title:              req.Title,
+		Description:        req.Description,
+		AreaM2:             req.AreaM2,
+		Price:              req.Price,
+		Currency:           req.Currency,
+		CleaningFee:        req.CleaningFee,
+		WeeklyDiscountPct:  req.WeeklyDiscountPct,
+		MonthlyDiscountPct: req.MonthlyDiscountPct,
+		BookingUnit:        domain.BookingUnit(req.BookingUnit),
+		MinSlotMinutes:     req.MinSlotMinutes,
+		CancellationPolicy: domain.CancellationPolicy(req.CancellationPolicy),
+		CancellationTiers:  req.CancellationTiers,
+		Phone:              req.Phone,
+		Address:            req.Address,
+		Latitude:           req.Latitude,
+		Longitude:          req.Longitude,
+	}
+	if (space.Latitude == nil) != (space.Longitude == nil) {
+		return nil, ErrInvalidLocation
+	}
+	if space.Currency == "" {
+		space.Currency = domain.DefaultCurrency
+	}
+	if space.BookingUnit == "" {
+		space.BookingUnit = domain.BookingUnitDay
+	}
+	if space.MinSlotMinutes == 0 {
+		space.MinSlotMinutes = domain.DefaultMinSlotMinutes
+	}
+	if err := normalizeCancellationPolicy(space); err != nil {
+		return nil, err
+	}
+	// Пространство и удобства пишутся в одной транзакции: неизвестный код не оставит пространство
+	if err := s.repo.Create(space, NormalizeAmenityCodes(req.Amenities)); err != nil {
+		return nil, err
 	}
 
-	if err := s.repo.Create(space); err != nil {
+	if err := s.loadDetails(space); err != nil {
 		return nil, err
 	}
 	return space, nil
 }
+
+func (s *SpaceService) UpdateSpace(id, ownerID int, req *domain.UpdateSpaceRequest) (*domain.Space, error) {
+	space, err := s.getOwned(id, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	oldPrice, oldCurrency := space.Price, space.Currency
+
+	if req.Title != nil {
+		space.Title = *req.Title
+	}
+	if req.Description != nil {
+		space.Description = *req.Description
+	}
+	if req.AreaM2 != nil {
+		space.AreaM2 = *req.AreaM2
+	}
+	if req.Price != nil {
+		space.Price = *req.Price
+	}
+	if req.Currency != nil {
+		space.Currency = *req.Currency
+	}
+	if req.CleaningFee != nil {
+		space.CleaningFee = *req.CleaningFee
+	}
+	if req.WeeklyDiscountPct != nil {
+		space.WeeklyDiscountPct = *req.WeeklyDiscountPct
+	}
+	if req.MonthlyDiscountPct != nil {
+		space.MonthlyDiscountPct = *req.MonthlyDiscountPct
+	}
+	if req.BookingUnit != nil {
+		space.BookingUnit = domain.BookingUnit(*req.BookingUnit)
+	}
+	if req.MinSlotMinutes != nil {
+		space.MinSlotMinutes = *req.MinSlotMinutes
+	}
+	if req.CancellationPolicy != nil {
+		space.CancellationPolicy = domain.CancellationPolicy(*req.CancellationPolicy)
+	}
+	if req.CancellationTiers != nil {
+		space.CancellationTiers = req.CancellationTiers
+	}
+	if req.Phone != nil {
+		space.Phone = *req.Phone
+	}
+	if req.Address != nil {
+		space.Address = *req.Address
+	}
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		return nil, ErrInvalidLocation
+	}
+	if req.Latitude != nil {
+		space.Latitude, space.Longitude = req.Latitude, req.Longitude
+	}
+	if err := normalizeCancellationPolicy(space); err != nil {
+		return nil, err
+	}
+	// nil — удобства в запросе не переданы и не меняются
+	var amenities []string
+	if req.Amenities != nil {
+		amenities = NormalizeAmenityCodes(req.Amenities)
+	}
+
+	if err := s.repo.Update(space, amenities); err != nil {
+		return nil, err
+	}
+	// Уведомляем только после того, как все изменения сохранены
+	if space.Price != oldPrice || space.Currency != oldCurrency {
+		// Сбой уведомлений не должен отменять изменение
+		if err := s.priceAlerts.SpacePriceChanged(context.Background(), space, oldCurrency); err != nil {
+			log.Printf("price alerts for space %d: %v", space.ID, err)
+		}
+	}
+
+	if err := s.loadDetails(space); err != nil {
+		return nil, err
+	}
+	return space, nil
+}
+
+func (s *SpaceService) ArchiveSpace(id, ownerID int) error {
+	return s.setActive(id, ownerID, false)
+}
+
+func (s *SpaceService) UnarchiveSpace(id, ownerID int) error {
+	return s.setActive(id, ownerID, true)
+}
+
+// DeleteSpace удаляет пространство; файлы фото удаляются после успешного удаления записи
+func (s *SpaceService) DeleteSpace(id, ownerID int) error {
+	if _, err := s.getOwned(id, ownerID); err != nil {
+		return err
+	}
+	photos, err := s.photos.ListBySpace(id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+
+	for _, p := range photos {
+		s.removePhotoFiles(context.Background(), &p)
+	}
+	return nil
+}
+
+func (s *SpaceService) setActive(id, ownerID int, active bool) error {
+	if _, err := s.getOwned(id, ownerID); err != nil {
+		return err
+	}
+	return s.repo.SetActive(id, active)
+}
+
+// QuotePrice считает стоимость бронирования пространства за период [from, to).
+// Период проверяется так же, как при бронировании, в том числе на максимальную длину.
+func (s *SpaceService) QuotePrice(spaceID int, from, to time.Time) (*domain.PriceQuote, error) {
+	space, err := s.repo.GetByID(spaceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPeriod(space, from, to); err != nil {
+		return nil, err
+	}
+	rules, err := s.rules.ListBySpace(spaceID)
+	if err != nil {
+		return nil, err
+	}
+	return pricing.Quote(space, rules, from, to)
+}
+
+func (s *SpaceService) ListPricingRules(spaceID, ownerID int) ([]domain.PricingRule, error) {
+	if _, err := s.getOwned(spaceID, ownerID); err != nil {
+		return nil, err
+	}
+	return s.rules.ListBySpace(spaceID)
+}
+
+func (s *SpaceService) CreatePricingRule(spaceID, ownerID int, req *domain.CreatePricingRuleRequest) (*domain.PricingRule, error) {
+	if _, err := s.getOwned(spaceID, ownerID); err != nil {
+		return nil, err
+	}
+	if (req.Price == nil) == (req.AdjustPct == nil) {
+		return nil, ErrInvalidPricingRule
+	}
+
+	rule := &domain.PricingRule{
+		SpaceID:    spaceID,
+		Name:       req.Name,
+		DaysOfWeek: req.DaysOfWeek,
+		Price:      req.Price,
+		AdjustPct:  req.AdjustPct,
+		Priority:   req.Priority,
+	}
+
+	var err error
+	if rule.DateFrom, err = parseOptionalDate(req.DateFrom); err != nil {
+		return nil, ErrInvalidPricingRule
+	}
+	if rule.DateTo, err = parseOptionalDate(req.DateTo); err != nil {
+		return nil, ErrInvalidPricingRule
+	}
+	if rule.DateFrom != nil && rule.DateTo != nil && rule.DateTo.Before(*rule.DateFrom) {
+		return nil, ErrInvalidDateRange
+	}
+
+	if err := s.rules.Create(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *SpaceService) DeletePricingRule(spaceID, ruleID, ownerID int) error {
+	if _, err := s.getOwned(spaceID, ownerID); err != nil {
+		return err
+	}
+	return s.rules.Delete(ruleID, spaceID)
+}
+
+func parseOptionalDate(v *string) (*time.Time, error) {
+	if v == nil || *v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(dateLayout, *v)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// PriceHistory возвращает историю базовой цены пространства владельцу
+func (s *SpaceService) PriceHistory(spaceID, ownerID int) ([]domain.PricePoint, error) {
+	if _, err := s.getOwned(spaceID, ownerID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListPriceHistory(spaceID)
+}
+
+// getOwned загружает пространство и проверяет, что оно принадлежит владельцу
+func (s *SpaceService) getOwned(id, ownerID int) (*domain.Space, error) {
+	space, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if space.OwnerID != ownerID {
+		return nil, ErrForbidden
+	}
+	return space, nil
+}
+
+// attachDetails дополняет пространства фото и удобствами, по одному запросу на каждое
+func (s *SpaceService) attachDetails(spaces []domain.Space) error {
+	if err := s.attachPhotos(spaces); err != nil {
+		return err
+	}
+
+	ids := make([]int, len(spaces))
+	for i := range spaces {
+		ids[i] = spaces[i].ID
+	}
+	byspace, err := s.amenities.ListBySpaces(ids)
+	if err != nil {
+		return err
+	}
+	for i := range spaces {
+		spaces[i].Amenities = byspace[spaces[i].ID]
+		if spaces[i].Amenities == nil {
+			spaces[i].Amenities = []domain.Amenity{}
+		}
+	}
+	return nil
+}
+
+func (s *SpaceService) loadDetails(space *domain.Space) error {
+	spaces := []domain.Space{*space}
+	if err := s.attachDetails(spaces); err != nil {
+		return err
+	}
+	*space = spaces[0]
+	return nil
+}
+
+// NormalizeAmenityCodes приводит коды удобств к нижнему регистру и убирает пустые и повторы
+func NormalizeAmenityCodes(codes []string) []string {
+	seen := make(map[string]bool, len(codes))
+	res := make([]string, 0, len(codes))
+	for _, c := range codes {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if c == "" || seen[c] {
+			continue
+		}
+		seen[c] = true
+		res = append(res, c)
+	}
+	return res
+}
@@ -0,0 +1,210 @@
+package storage
+
+import (
+	"context"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	testBucket    = "spacebook"
+	testAccessKey = "test-access"
+	testSecretKey = "test-secret"
+	testRegion    = "us-east-1"
+)
+
+type fakeObject struct {
+	data        []byte
+	contentType string
+}
+
+// fakeS3 — S3-совместимый сервер в памяти: проверяет подпись Signature V4
+// и хранит объекты по пути /{bucket}/{key}
+type fakeS3 struct {
+	t       *testing.T
+	mu      sync.Mutex
+	objects map[string]fakeObject
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *httptest.Server) {
+	f := &fakeS3{t: t, objects: map[string]fakeObject{}}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !f.validSignature(r, body) {
+		http.Error(w, "<Error><Code>SignatureDoesNotMatch</Code></Error>", http.StatusForbidden)
+		return
+	}
+
+	prefix := "/" + testBucket + "/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.Error(w, "<Error><Code>NoSuchBucket</Code></Error>", http.StatusNotFound)
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, prefix)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		f.objects[key] = fakeObject{data: body, contentType: r.Header.Get("Content-Type")}
+		w.WriteHeader(http.StatusOK)
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// validSignature пересчитывает подпись по полученному запросу так, как это делает S3
+func (f *fakeS3) validSignature(r *http.Request, body []byte) bool {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 ") {
+		return false
+	}
+	fields := map[string]string{}
+	for _, part := range strings.Split(strings.TrimPrefix(auth, "AWS4-HMAC-SHA256 "), ", ") {
+		k, v, ok := strings.Cut(part, "=")
+		if !ok {
+			return false
+		}
+		fields[k] = v
+	}
+
+	credential := strings.Split(fields["Credential"], "/")
+	if len(credential) != 5 || credential[0] != testAccessKey {
+		return false
+	}
+	day, region := credential[1], credential[2]
+
+	payloadHash := r.Header.Get("X-Amz-Content-Sha256")
+	if payloadHash != sha256Hex(body) {
+		f.t.Errorf("payload hash %s does not match body", payloadHash)
+		return false
+	}
+
+	var canonicalHeaders strings.Builder
+	for _, h := range strings.Split(fields["SignedHeaders"], ";") {
+		v := r.Header.Get(h)
+		if h == "host" {
+			v = r.Host
+		}
+		canonicalHeaders.WriteString(h + ":" + strings.TrimSpace(v) + "\n")
+	}
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		r.URL.EscapedPath(),
+		r.URL.RawQuery,
+		canonicalHeaders.String(),
+		fields["SignedHeaders"],
+		payloadHash,
+	}, "\n")
+
+	amzDate := r.Header.Get("X-Amz-Date")
+	scope := day + "/" + region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+testSecretKey), day)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign)) == fields["Signature"]
+}
+
+func (f *fakeS3) object(key string) (fakeObject, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	obj, ok := f.objects[key]
+	return obj, ok
+}
+
+func newTestS3Storage(endpoint string) *S3Storage {
+	return NewS3Storage(S3Config{
+		Endpoint:  endpoint + "/",
+		Region:    testRegion,
+		Bucket:    testBucket,
+		AccessKey: testAccessKey,
+		SecretKey: testSecretKey,
+	})
+}
+
+func TestS3PutAndDelete(t *testing.T) {
+	fake, srv := newFakeS3(t)
+	s := newTestS3Storage(srv.URL)
+	ctx := context.Background()
+
+	keys := []string{"spaces/1/photo.jpg", "spaces/1/фото с пробелом.png"}
+	for _, key := range keys {
+		data := []byte("image bytes of " + key)
+		if err := s.Put(ctx, key, data, "image/jpeg"); err != nil {
+			t.Fatalf("put %q: %v", key, err)
+		}
+
+		obj, ok := fake.object(key)
+		if !ok {
+			t.Fatalf("object %q was not stored", key)
+		}
+		if string(obj.data) != string(data) {
+			t.Errorf("object %q: got %q, want %q", key, obj.data, data)
+		}
+		if obj.contentType != "image/jpeg" {
+			t.Errorf("object %q: content type %q, want image/jpeg", key, obj.contentType)
+		}
+	}
+
+	if err := s.Delete(ctx, keys[0]); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, ok := fake.object(keys[0]); ok {
+		t.Errorf("object %q still exists after delete", keys[0])
+	}
+	if _, ok := fake.object(keys[1]); !ok {
+		t.Errorf("object %q was deleted by mistake", keys[1])
+	}
+
+	// Удаление отсутствующего объекта — не ошибка
+	if err := s.Delete(ctx, "spaces/1/missing.jpg"); err != nil {
+		t.Fatalf("delete missing: %v", err)
+	}
+}
+
+func TestS3WrongCredentials(t *testing.T) {
+	_, srv := newFakeS3(t)
+	s := NewS3Storage(S3Config{
+		Endpoint:  srv.URL,
+		Bucket:    testBucket,
+		AccessKey: testAccessKey,
+		SecretKey: "wrong-secret",
+	})
+
+	err := s.Put(context.Background(), "spaces/1/photo.jpg", []byte("data"), "image/jpeg")
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Fatalf("put with wrong secret: got %v, want 403 error", err)
+	}
+}
+
+func TestS3URL(t *testing.T) {
+	s := newTestS3Storage("http://minio:9000")
+	if got, want := s.URL("spaces/1/a.jpg"), "http://minio:9000/spacebook/spaces/1/a.jpg"; got != want {
+		t.Errorf("default URL: got %q, want %q", got, want)
+	}
+
+	s = NewS3Storage(S3Config{Endpoint: "http://minio:9000", Bucket: testBucket, PublicURL: "https://cdn.example.com/"})
+	if got, want := s.URL("spaces/1/a.jpg"), "https://cdn.example.com/spaces/1/a.jpg"; got != want {
+		t.Errorf("public URL: got %q, want %q", got, want)
+	}
+}
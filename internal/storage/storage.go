@@ -0,0 +1,16 @@
+package storage
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrUnknownDriver = errors.New("unknown storage driver")
+
+// Storage хранит загруженные файлы по ключу вида spaces/12/abc.jpg
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Delete(ctx context.Context, key string) error
+	// URL возвращает публичную ссылку на файл
+	URL(key string) string
+}
@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"SpaceBookProject/internal/services"
+)
+
+// BookingCompletionJob периодически переводит прошедшие одобренные брони в статус completed
+type BookingCompletionJob struct {
+	bookings *services.BookingService
+	interval time.Duration
+}
+
+func NewBookingCompletionJob(bookings *services.BookingService, interval time.Duration) *BookingCompletionJob {
+	return &BookingCompletionJob{
+		bookings: bookings,
+		interval: interval,
+	}
+}
+
+func (j *BookingCompletionJob) Run(ctx context.Context) {
+	log.Println("[worker] booking completion job started")
+	defer log.Println("[worker] booking completion job stopped")
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.tick()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *BookingCompletionJob) tick() {
+	n, err := j.bookings.CompleteFinishedBookings()
+	if err != nil {
+		log.Printf("[worker] failed to complete finished bookings: %v\n", err)
+		return
+	}
+	if n > 0 {
+		log.Printf("[worker] completed %d bookings\n", n)
+	}
+}
@@ -8,29 +8,20 @@ import (
 	"SpaceBookProject/internal/domain"
 )
 
-type BookingEventWorker struct {
-	Events <-chan domain.BookingEvent
-}
+// BookingEventWorker обрабатывает события по броням, которые доставляет OutboxDispatcher
+type BookingEventWorker struct{}
 
-func NewBookingEventWorker(events <-chan domain.BookingEvent) *BookingEventWorker {
-	return &BookingEventWorker{Events: events}
+func NewBookingEventWorker() *BookingEventWorker {
+	return &BookingEventWorker{}
 }
 
-func (w *BookingEventWorker) Run(ctx context.Context) {
-	log.Println("[worker] booking event worker started")
-	defer log.Println("[worker] booking event worker stopped")
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case evt := <-w.Events:
-			log.Printf(
-				"[worker] event=%s booking_id=%d space_id=%d tenant_id=%d at=%s\n",
-				evt.Type, evt.BookingID, evt.SpaceID, evt.TenantID,
-				evt.At.Format(time.RFC3339),
-			)
-			// TODO: here insert into DB, send email, etc.
-		}
-	}
+// Handle подходит как BookingEventHandler для OutboxDispatcher.Subscribe
+func (w *BookingEventWorker) Handle(ctx context.Context, evt domain.BookingEvent) error {
+	log.Printf(
+		"[worker] event=%s booking_id=%d space_id=%d tenant_id=%d conversation_id=%d sender_id=%d at=%s\n",
+		evt.Type, evt.BookingID, evt.SpaceID, evt.TenantID, evt.ConversationID, evt.SenderID,
+		evt.At.Format(time.RFC3339),
+	)
+	// TODO: here insert into DB, send email, etc.
+	return nil
 }
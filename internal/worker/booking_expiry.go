@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"SpaceBookProject/internal/services"
+)
+
+// BookingExpiryJob периодически переводит зависшие заявки в статус expired
+type BookingExpiryJob struct {
+	bookings   *services.BookingService
+	pendingTTL time.Duration
+	interval   time.Duration
+}
+
+func NewBookingExpiryJob(bookings *services.BookingService, pendingTTL, interval time.Duration) *BookingExpiryJob {
+	return &BookingExpiryJob{
+		bookings:   bookings,
+		pendingTTL: pendingTTL,
+		interval:   interval,
+	}
+}
+
+func (j *BookingExpiryJob) Run(ctx context.Context) {
+	log.Println("[worker] booking expiry job started")
+	defer log.Println("[worker] booking expiry job stopped")
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.tick()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *BookingExpiryJob) tick() {
+	n, err := j.bookings.ExpirePendingBookings(j.pendingTTL)
+	if err != nil {
+		log.Printf("[worker] failed to expire pending bookings: %v\n", err)
+		return
+	}
+	if n > 0 {
+		log.Printf("[worker] expired %d pending bookings\n", n)
+	}
+}
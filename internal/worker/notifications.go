@@ -0,0 +1,27 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"SpaceBookProject/internal/domain"
+)
+
+// NotificationWorker доставляет уведомления из notification_outbox, которые передаёт OutboxDispatcher
+type NotificationWorker struct{}
+
+func NewNotificationWorker() *NotificationWorker {
+	return &NotificationWorker{}
+}
+
+// Handle подходит как NotificationHandler для OutboxDispatcher.Subscribe
+func (w *NotificationWorker) Handle(ctx context.Context, evt domain.NotificationEvent) error {
+	log.Printf(
+		"[worker] notification=%s user_id=%d space_id=%d saved_search_id=%d old_price=%d new_price=%d at=%s\n",
+		evt.Type, evt.UserID, evt.SpaceID, evt.SavedSearchID, evt.OldPrice, evt.NewPrice,
+		evt.At.Format(time.RFC3339),
+	)
+	// TODO: send email / push
+	return nil
+}
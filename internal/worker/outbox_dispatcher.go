@@ -0,0 +1,141 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"SpaceBookProject/internal/domain"
+	"SpaceBookProject/internal/repository"
+)
+
+const (
+	// На это время взятое событие скрыто от других диспетчеров
+	outboxLease = 5 * time.Minute
+	// Верхняя граница задержки между повторными попытками
+	outboxMaxBackoff = time.Hour
+	// Как часто удалять доставленные события старше Retention
+	outboxCleanupInterval = time.Hour
+)
+
+// OutboxHandler обрабатывает событие из outbox. Доставка «хотя бы один раз»:
+// при ошибке любого обработчика событие повторяется целиком, поэтому
+// обработчики должны спокойно переносить повторы.
+type OutboxHandler[E any] func(ctx context.Context, evt E) error
+
+// BookingEventHandler обрабатывает событие по брони
+type BookingEventHandler = OutboxHandler[domain.BookingEvent]
+
+// NotificationHandler доставляет уведомление пользователю
+type NotificationHandler = OutboxHandler[domain.NotificationEvent]
+
+type OutboxOptions struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	RetryBackoff time.Duration
+	Retention    time.Duration
+}
+
+// OutboxDispatcher забирает события из outbox-таблицы и передаёт их обработчикам.
+// Несколько экземпляров приложения могут работать параллельно.
+// name различает диспетчеры разных таблиц в логах.
+type OutboxDispatcher[E any] struct {
+	name     string
+	outbox   *repository.OutboxRepository[E]
+	opts     OutboxOptions
+	handlers []OutboxHandler[E]
+
+	lastCleanup time.Time
+}
+
+func NewOutboxDispatcher[E any](name string, outbox *repository.OutboxRepository[E], opts OutboxOptions) *OutboxDispatcher[E] {
+	return &OutboxDispatcher[E]{
+		name:   name,
+		outbox: outbox,
+		opts:   opts,
+	}
+}
+
+// Subscribe регистрирует обработчик; вызывать до Run
+func (d *OutboxDispatcher[E]) Subscribe(h OutboxHandler[E]) {
+	d.handlers = append(d.handlers, h)
+}
+
+func (d *OutboxDispatcher[E]) Run(ctx context.Context) {
+	log.Printf("[worker] %s dispatcher started\n", d.name)
+	defer log.Printf("[worker] %s dispatcher stopped\n", d.name)
+
+	ticker := time.NewTicker(d.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick доставляет все накопившиеся события: пока выборка полная, забираем следующую
+func (d *OutboxDispatcher[E]) tick(ctx context.Context) {
+	for ctx.Err() == nil {
+		events, err := d.outbox.Claim(d.opts.BatchSize, outboxLease)
+		if err != nil {
+			log.Printf("[worker] failed to claim %s events: %v\n", d.name, err)
+			return
+		}
+		for _, e := range events {
+			d.dispatch(ctx, e)
+		}
+		if len(events) < d.opts.BatchSize {
+			break
+		}
+	}
+
+	if time.Since(d.lastCleanup) >= outboxCleanupInterval {
+		d.lastCleanup = time.Now()
+		n, err := d.outbox.DeleteProcessedBefore(time.Now().Add(-d.opts.Retention))
+		if err != nil {
+			log.Printf("[worker] failed to clean up %s: %v\n", d.name, err)
+		} else if n > 0 {
+			log.Printf("[worker] removed %d delivered %s events\n", n, d.name)
+		}
+	}
+}
+
+func (d *OutboxDispatcher[E]) dispatch(ctx context.Context, e domain.OutboxEvent[E]) {
+	var deliverErr error
+	for _, h := range d.handlers {
+		if deliverErr = h(ctx, e.Event); deliverErr != nil {
+			break
+		}
+	}
+
+	var err error
+	switch {
+	case deliverErr == nil:
+		err = d.outbox.MarkDone(e.ID)
+	case e.Attempts >= d.opts.MaxAttempts:
+		log.Printf("[worker] %s event %d failed after %d attempts: %v\n", d.name, e.ID, e.Attempts, deliverErr)
+		err = d.outbox.Fail(e.ID, deliverErr.Error())
+	default:
+		err = d.outbox.Retry(e.ID, d.backoff(e.Attempts), deliverErr.Error())
+	}
+	if err != nil {
+		// Строка останется взятой до истечения outboxLease и будет доставлена повторно
+		log.Printf("[worker] failed to update %s event %d: %v\n", d.name, e.ID, err)
+	}
+}
+
+// backoff — задержка после attempts неудачных попыток: RetryBackoff, 2×, 4×… до outboxMaxBackoff
+func (d *OutboxDispatcher[E]) backoff(attempts int) time.Duration {
+	delay := d.opts.RetryBackoff
+	for i := 1; i < attempts && delay < outboxMaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, outboxMaxBackoff)
+}
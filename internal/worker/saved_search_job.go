@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"SpaceBookProject/internal/services"
+)
+
+// SavedSearchJob периодически ищет новые пространства по сохранённым поискам
+type SavedSearchJob struct {
+	searches *services.SavedSearchService
+	interval time.Duration
+}
+
+func NewSavedSearchJob(searches *services.SavedSearchService, interval time.Duration) *SavedSearchJob {
+	return &SavedSearchJob{
+		searches: searches,
+		interval: interval,
+	}
+}
+
+func (j *SavedSearchJob) Run(ctx context.Context) {
+	log.Println("[worker] saved search job started")
+	defer log.Println("[worker] saved search job stopped")
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.tick()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *SavedSearchJob) tick() {
+	n, err := j.searches.MatchNewSpaces()
+	if err != nil {
+		log.Printf("[worker] failed to match saved searches: %v\n", err)
+		return
+	}
+	if n > 0 {
+		log.Printf("[worker] sent %d saved search notifications\n", n)
+	}
+}
@@ -0,0 +1 @@
+ALTER TABLE bookings DROP CONSTRAINT IF EXISTS bookings_no_approved_overlap;
@@ -0,0 +1,37 @@
+-- btree_gist нужен, чтобы в одном GiST-ограничении сравнивать space_id через "="
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+-- Две одобренные брони одного пространства не могут пересекаться по датам.
+-- date_to — день выезда, поэтому диапазон полуоткрытый: '[)'
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM pg_constraint
+        WHERE conname = 'bookings_no_approved_overlap' AND conrelid = 'bookings'::regclass
+    ) THEN
+        RETURN;
+    END IF;
+
+    -- До этого ограничения параллельные одобрения могли пропустить пересечения;
+    -- их нужно разобрать вручную, автоматически выбрать «правильную» бронь нельзя
+    IF EXISTS (
+        SELECT 1
+        FROM bookings a
+        JOIN bookings b ON b.space_id = a.space_id AND b.id > a.id
+        WHERE a.status = 'approved' AND b.status = 'approved'
+          AND a.date_from < b.date_to AND b.date_from < a.date_to
+    ) THEN
+        RAISE EXCEPTION 'bookings contain overlapping approved bookings, bookings_no_approved_overlap cannot be added'
+            USING HINT = 'Find them with: SELECT a.id, b.id FROM bookings a JOIN bookings b ON b.space_id = a.space_id AND b.id > a.id '
+                         'WHERE a.status = ''approved'' AND b.status = ''approved'' AND a.date_from < b.date_to AND b.date_from < a.date_to; '
+                         'cancel or reject the extra bookings and run the migration again.';
+    END IF;
+
+    ALTER TABLE bookings
+        ADD CONSTRAINT bookings_no_approved_overlap
+        EXCLUDE USING gist (
+            space_id WITH =,
+            daterange(date_from, date_to, '[)') WITH &&
+        )
+        WHERE (status = 'approved');
+END $$;
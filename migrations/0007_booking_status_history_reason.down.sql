@@ -0,0 +1,2 @@
+ALTER TABLE booking_status_history DROP COLUMN IF EXISTS created_at;
+ALTER TABLE booking_status_history DROP COLUMN IF EXISTS reason;
@@ -0,0 +1,3 @@
+-- Причина смены статуса и время записи, которые пишет BookingStatusHistoryRepository
+ALTER TABLE booking_status_history ADD COLUMN IF NOT EXISTS reason TEXT;
+ALTER TABLE booking_status_history ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now();
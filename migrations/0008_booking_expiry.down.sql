@@ -0,0 +1,12 @@
+ALTER TABLE booking_status_history DROP CONSTRAINT IF EXISTS booking_status_history_actor_check;
+ALTER TABLE booking_status_history DROP COLUMN IF EXISTS actor;
+DELETE FROM booking_status_history WHERE changed_by IS NULL;
+ALTER TABLE booking_status_history ALTER COLUMN changed_by SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_bookings_pending_created_at;
+
+UPDATE bookings SET status = 'cancelled' WHERE status = 'expired';
+ALTER TABLE bookings DROP CONSTRAINT IF EXISTS bookings_status_check;
+ALTER TABLE bookings
+    ADD CONSTRAINT bookings_status_check
+    CHECK (status IN ('pending', 'approved', 'rejected', 'cancelled'));
@@ -0,0 +1,25 @@
+-- Новый статус для заявок, которые владелец не успел рассмотреть
+ALTER TABLE bookings DROP CONSTRAINT IF EXISTS bookings_status_check;
+ALTER TABLE bookings
+    ADD CONSTRAINT bookings_status_check
+    CHECK (status IN ('pending', 'approved', 'rejected', 'cancelled', 'expired'));
+
+CREATE INDEX IF NOT EXISTS idx_bookings_pending_created_at
+    ON bookings(created_at)
+    WHERE status = 'pending';
+
+-- Статус может сменить система, у которой нет пользователя:
+-- changed_by становится необязательным, а кто сменил — пишем в actor
+ALTER TABLE booking_status_history ALTER COLUMN changed_by DROP NOT NULL;
+ALTER TABLE booking_status_history ADD COLUMN IF NOT EXISTS actor VARCHAR(20);
+
+UPDATE booking_status_history h
+SET actor = u.role
+FROM users u
+WHERE u.id = h.changed_by
+  AND h.actor IS NULL;
+
+ALTER TABLE booking_status_history ALTER COLUMN actor SET NOT NULL;
+ALTER TABLE booking_status_history
+    ADD CONSTRAINT booking_status_history_actor_check
+    CHECK (actor IN ('tenant', 'owner', 'system'));
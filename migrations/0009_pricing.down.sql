@@ -0,0 +1,7 @@
+ALTER TABLE bookings DROP COLUMN IF EXISTS currency;
+ALTER TABLE bookings DROP COLUMN IF EXISTS total_price;
+
+ALTER TABLE spaces DROP COLUMN IF EXISTS monthly_discount_pct;
+ALTER TABLE spaces DROP COLUMN IF EXISTS weekly_discount_pct;
+ALTER TABLE spaces DROP COLUMN IF EXISTS cleaning_fee;
+ALTER TABLE spaces DROP COLUMN IF EXISTS currency;
@@ -0,0 +1,10 @@
+ALTER TABLE spaces ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'KZT';
+ALTER TABLE spaces ADD COLUMN IF NOT EXISTS cleaning_fee INTEGER NOT NULL DEFAULT 0 CHECK (cleaning_fee >= 0);
+ALTER TABLE spaces ADD COLUMN IF NOT EXISTS weekly_discount_pct SMALLINT NOT NULL DEFAULT 0
+    CHECK (weekly_discount_pct BETWEEN 0 AND 100);
+ALTER TABLE spaces ADD COLUMN IF NOT EXISTS monthly_discount_pct SMALLINT NOT NULL DEFAULT 0
+    CHECK (monthly_discount_pct BETWEEN 0 AND 100);
+
+-- Стоимость брони на момент создания; у броней, созданных до миграции, остаётся NULL
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS total_price INTEGER;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS currency CHAR(3);
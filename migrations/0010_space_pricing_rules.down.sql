@@ -0,0 +1 @@
+DROP TABLE IF EXISTS space_pricing_rules;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS space_pricing_rules (
+    id           SERIAL PRIMARY KEY,
+    space_id     INTEGER NOT NULL REFERENCES spaces(id) ON DELETE CASCADE,
+    name         VARCHAR(100) NOT NULL,
+    -- Период действия, обе границы включительно; NULL — без ограничения
+    date_from    DATE,
+    date_to      DATE,
+    -- Дни недели: 0 — воскресенье ... 6 — суббота; NULL — все дни
+    days_of_week SMALLINT[],
+    -- Ровно одно из двух: фиксированная цена за ночь или наценка/скидка в процентах
+    price        INTEGER CHECK (price > 0),
+    adjust_pct   INTEGER CHECK (adjust_pct >= -100),
+    priority     INTEGER NOT NULL DEFAULT 0,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK ((price IS NULL) <> (adjust_pct IS NULL)),
+    CHECK (date_from IS NULL OR date_to IS NULL OR date_from <= date_to),
+    CHECK (days_of_week <@ ARRAY[0, 1, 2, 3, 4, 5, 6]::SMALLINT[])
+);
+
+CREATE INDEX IF NOT EXISTS idx_space_pricing_rules_space_id ON space_pricing_rules(space_id);
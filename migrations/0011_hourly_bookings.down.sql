@@ -0,0 +1,16 @@
+ALTER TABLE bookings DROP CONSTRAINT IF EXISTS bookings_no_approved_overlap;
+
+ALTER TABLE bookings
+    ALTER COLUMN date_from TYPE DATE USING (date_from AT TIME ZONE 'UTC')::date,
+    ALTER COLUMN date_to   TYPE DATE USING (date_to AT TIME ZONE 'UTC')::date;
+
+ALTER TABLE bookings
+    ADD CONSTRAINT bookings_no_approved_overlap
+    EXCLUDE USING gist (
+        space_id WITH =,
+        daterange(date_from, date_to, '[)') WITH &&
+    )
+    WHERE (status = 'approved');
+
+ALTER TABLE spaces DROP COLUMN IF EXISTS min_slot_minutes;
+ALTER TABLE spaces DROP COLUMN IF EXISTS booking_unit;
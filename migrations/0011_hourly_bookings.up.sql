@@ -0,0 +1,20 @@
+-- Пространство сдаётся посуточно (day) или почасово (hour) с минимальной длительностью слота
+ALTER TABLE spaces ADD COLUMN IF NOT EXISTS booking_unit VARCHAR(10) NOT NULL DEFAULT 'day'
+    CHECK (booking_unit IN ('day', 'hour'));
+ALTER TABLE spaces ADD COLUMN IF NOT EXISTS min_slot_minutes INTEGER NOT NULL DEFAULT 60
+    CHECK (min_slot_minutes > 0);
+
+-- Брони хранят моменты времени; посуточные брони начинаются и заканчиваются в полночь UTC
+ALTER TABLE bookings DROP CONSTRAINT IF EXISTS bookings_no_approved_overlap;
+
+ALTER TABLE bookings
+    ALTER COLUMN date_from TYPE TIMESTAMPTZ USING date_from::timestamp AT TIME ZONE 'UTC',
+    ALTER COLUMN date_to   TYPE TIMESTAMPTZ USING date_to::timestamp AT TIME ZONE 'UTC';
+
+ALTER TABLE bookings
+    ADD CONSTRAINT bookings_no_approved_overlap
+    EXCLUDE USING gist (
+        space_id WITH =,
+        tstzrange(date_from, date_to, '[)') WITH &&
+    )
+    WHERE (status = 'approved');
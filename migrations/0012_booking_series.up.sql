@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS booking_series (
+    id          SERIAL PRIMARY KEY,
+    space_id    INTEGER NOT NULL REFERENCES spaces(id) ON DELETE CASCADE,
+    tenant_id   INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    freq        VARCHAR(10) NOT NULL CHECK (freq IN ('weekly', 'monthly')),
+    "interval"  INTEGER NOT NULL DEFAULT 1 CHECK ("interval" > 0),
+    until       DATE,
+    count       INTEGER CHECK (count > 0),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (until IS NOT NULL OR count IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_series_tenant_id ON booking_series(tenant_id);
+
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS series_id INTEGER REFERENCES booking_series(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_bookings_series_id ON bookings(series_id);
@@ -0,0 +1,6 @@
+ALTER TABLE booking_status_history DROP COLUMN IF EXISTS new_date_to;
+ALTER TABLE booking_status_history DROP COLUMN IF EXISTS new_date_from;
+ALTER TABLE booking_status_history DROP COLUMN IF EXISTS old_date_to;
+ALTER TABLE booking_status_history DROP COLUMN IF EXISTS old_date_from;
+
+DROP TABLE IF EXISTS booking_change_requests;
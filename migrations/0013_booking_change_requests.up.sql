@@ -0,0 +1,30 @@
+-- Заявки арендатора на перенос дат брони
+CREATE TABLE IF NOT EXISTS booking_change_requests (
+    id            SERIAL PRIMARY KEY,
+    booking_id    INTEGER NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    requested_by  INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    old_date_from TIMESTAMPTZ NOT NULL,
+    old_date_to   TIMESTAMPTZ NOT NULL,
+    new_date_from TIMESTAMPTZ NOT NULL,
+    new_date_to   TIMESTAMPTZ NOT NULL,
+    total_price   INTEGER,
+    currency      CHAR(3),
+    status        VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'approved', 'declined', 'withdrawn')),
+    reason        TEXT,
+    decided_by    INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    decided_at    TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (new_date_from < new_date_to)
+);
+
+-- У брони может быть только одна нерассмотренная заявка на перенос
+CREATE UNIQUE INDEX IF NOT EXISTS idx_booking_change_requests_pending
+    ON booking_change_requests(booking_id)
+    WHERE status = 'pending';
+
+-- Перенос дат фиксируется в истории вместе со старым и новым периодом
+ALTER TABLE booking_status_history ADD COLUMN IF NOT EXISTS old_date_from TIMESTAMPTZ;
+ALTER TABLE booking_status_history ADD COLUMN IF NOT EXISTS old_date_to TIMESTAMPTZ;
+ALTER TABLE booking_status_history ADD COLUMN IF NOT EXISTS new_date_from TIMESTAMPTZ;
+ALTER TABLE booking_status_history ADD COLUMN IF NOT EXISTS new_date_to TIMESTAMPTZ;
@@ -0,0 +1,7 @@
+ALTER TABLE bookings DROP COLUMN IF EXISTS refund_amount;
+ALTER TABLE bookings DROP COLUMN IF EXISTS refund_pct;
+ALTER TABLE bookings DROP COLUMN IF EXISTS cancellation_tiers;
+ALTER TABLE bookings DROP COLUMN IF EXISTS cancellation_policy;
+
+ALTER TABLE spaces DROP COLUMN IF EXISTS cancellation_tiers;
+ALTER TABLE spaces DROP COLUMN IF EXISTS cancellation_policy;
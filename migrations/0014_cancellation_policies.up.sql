@@ -0,0 +1,11 @@
+-- Политика отмены пространства; ступени хранятся только для custom
+ALTER TABLE spaces ADD COLUMN IF NOT EXISTS cancellation_policy VARCHAR(20) NOT NULL DEFAULT 'flexible'
+    CHECK (cancellation_policy IN ('flexible', 'moderate', 'strict', 'custom'));
+ALTER TABLE spaces ADD COLUMN IF NOT EXISTS cancellation_tiers JSONB;
+
+-- Политика фиксируется в брони при создании, чтобы её изменение не влияло на старые брони.
+-- При отмене сохраняется рассчитанный возврат.
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS cancellation_policy VARCHAR(20);
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS cancellation_tiers JSONB;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS refund_pct SMALLINT CHECK (refund_pct BETWEEN 0 AND 100);
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS refund_amount INTEGER;
@@ -0,0 +1,13 @@
+-- Счётчик отмен одобренных броней по инициативе владельца
+ALTER TABLE spaces ADD COLUMN IF NOT EXISTS owner_cancellations INTEGER NOT NULL DEFAULT 0;
+
+UPDATE spaces s
+SET owner_cancellations = c.cnt
+FROM (
+    SELECT b.space_id, COUNT(*) AS cnt
+    FROM booking_status_history h
+    JOIN bookings b ON b.id = h.booking_id
+    WHERE h.new_status = 'cancelled' AND h.actor = 'owner'
+    GROUP BY b.space_id
+) c
+WHERE c.space_id = s.id;
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_bookings_approved_date_to;
+
+ALTER TABLE bookings DROP COLUMN IF EXISTS checked_out_at;
+ALTER TABLE bookings DROP COLUMN IF EXISTS checked_in_at;
+
+UPDATE bookings SET status = 'approved' WHERE status IN ('completed', 'no_show');
+ALTER TABLE bookings DROP CONSTRAINT IF EXISTS bookings_status_check;
+ALTER TABLE bookings
+    ADD CONSTRAINT bookings_status_check
+    CHECK (status IN ('pending', 'approved', 'rejected', 'cancelled', 'expired'));
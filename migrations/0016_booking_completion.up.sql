@@ -0,0 +1,13 @@
+-- Завершённые проживания и неявки
+ALTER TABLE bookings DROP CONSTRAINT IF EXISTS bookings_status_check;
+ALTER TABLE bookings
+    ADD CONSTRAINT bookings_status_check
+    CHECK (status IN ('pending', 'approved', 'rejected', 'cancelled', 'expired', 'completed', 'no_show'));
+
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS checked_in_at TIMESTAMPTZ;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS checked_out_at TIMESTAMPTZ;
+
+-- Для фоновой задачи, которая завершает прошедшие брони
+CREATE INDEX IF NOT EXISTS idx_bookings_approved_date_to
+    ON bookings(date_to)
+    WHERE status = 'approved';
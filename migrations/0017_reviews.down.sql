@@ -0,0 +1,7 @@
+ALTER TABLE users DROP COLUMN IF EXISTS rating_count;
+ALTER TABLE users DROP COLUMN IF EXISTS rating_avg;
+
+ALTER TABLE spaces DROP COLUMN IF EXISTS rating_count;
+ALTER TABLE spaces DROP COLUMN IF EXISTS rating_avg;
+
+DROP TABLE IF EXISTS reviews;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS space_photos (
+    id SERIAL PRIMARY KEY,
+    space_id INTEGER NOT NULL REFERENCES spaces(id) ON DELETE CASCADE,
+    storage_key VARCHAR(255) NOT NULL,
+    thumb_key VARCHAR(255) NOT NULL,
+    content_type VARCHAR(50) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    is_cover BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_space_photos_space ON space_photos(space_id, position);
+
+-- Не больше одной обложки у пространства
+CREATE UNIQUE INDEX IF NOT EXISTS idx_space_photos_cover
+    ON space_photos(space_id)
+    WHERE is_cover;
@@ -0,0 +1,29 @@
+-- Справочник удобств; коды используются в фильтре каталога (?amenities=wifi,parking)
+CREATE TABLE IF NOT EXISTS amenities (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(50) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS space_amenities (
+    space_id INTEGER NOT NULL REFERENCES spaces(id) ON DELETE CASCADE,
+    amenity_id INTEGER NOT NULL REFERENCES amenities(id) ON DELETE CASCADE,
+    PRIMARY KEY (space_id, amenity_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_space_amenities_amenity ON space_amenities(amenity_id);
+
+INSERT INTO amenities (code, name) VALUES
+    ('wifi', 'Wi-Fi'),
+    ('parking', 'Parking'),
+    ('projector', 'Projector'),
+    ('kitchen', 'Kitchen'),
+    ('air_conditioning', 'Air conditioning'),
+    ('heating', 'Heating'),
+    ('whiteboard', 'Whiteboard'),
+    ('coffee', 'Coffee and tea'),
+    ('printer', 'Printer'),
+    ('meeting_room', 'Meeting room'),
+    ('wheelchair_access', 'Wheelchair access'),
+    ('24_7_access', '24/7 access')
+ON CONFLICT (code) DO NOTHING;
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_spaces_coordinates;
+ALTER TABLE spaces DROP CONSTRAINT IF EXISTS spaces_coordinates_pair_check;
+
+ALTER TABLE spaces DROP COLUMN IF EXISTS longitude;
+ALTER TABLE spaces DROP COLUMN IF EXISTS latitude;
+ALTER TABLE spaces DROP COLUMN IF EXISTS country;
+ALTER TABLE spaces DROP COLUMN IF EXISTS postal_code;
+ALTER TABLE spaces DROP COLUMN IF EXISTS region;
+ALTER TABLE spaces DROP COLUMN IF EXISTS city;
+ALTER TABLE spaces DROP COLUMN IF EXISTS address_line;
@@ -0,0 +1,25 @@
+-- Адрес и координаты пространства; координаты задаются парой или не задаются вовсе
+ALTER TABLE spaces ADD COLUMN IF NOT EXISTS address_line VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE spaces ADD COLUMN IF NOT EXISTS city VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE spaces ADD COLUMN IF NOT EXISTS region VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE spaces ADD COLUMN IF NOT EXISTS postal_code VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE spaces ADD COLUMN IF NOT EXISTS country VARCHAR(2) NOT NULL DEFAULT '';
+ALTER TABLE spaces ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION CHECK (latitude BETWEEN -90 AND 90);
+ALTER TABLE spaces ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION CHECK (longitude BETWEEN -180 AND 180);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint
+        WHERE conname = 'spaces_coordinates_pair_check' AND conrelid = 'spaces'::regclass
+    ) THEN
+        ALTER TABLE spaces
+            ADD CONSTRAINT spaces_coordinates_pair_check
+            CHECK ((latitude IS NULL) = (longitude IS NULL));
+    END IF;
+END $$;
+
+-- Предфильтр поиска по радиусу и выборка для карты идут по прямоугольнику координат
+CREATE INDEX IF NOT EXISTS idx_spaces_coordinates
+    ON spaces(latitude, longitude)
+    WHERE latitude IS NOT NULL;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_favorites_user_created;
+DROP INDEX IF EXISTS idx_bookings_space_date_from;
+DROP INDEX IF EXISTS idx_bookings_tenant_date_from;
+DROP INDEX IF EXISTS idx_spaces_active_rating;
+DROP INDEX IF EXISTS idx_spaces_active_area;
+DROP INDEX IF EXISTS idx_spaces_active_price;
+DROP INDEX IF EXISTS idx_spaces_active_created;
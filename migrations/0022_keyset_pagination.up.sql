@@ -0,0 +1,10 @@
+-- Индексы под keyset-пагинацию: (ключ сортировки, id)
+CREATE INDEX IF NOT EXISTS idx_spaces_active_created ON spaces(created_at, id) WHERE is_active;
+CREATE INDEX IF NOT EXISTS idx_spaces_active_price ON spaces(price, id) WHERE is_active;
+CREATE INDEX IF NOT EXISTS idx_spaces_active_area ON spaces(area_m2, id) WHERE is_active;
+CREATE INDEX IF NOT EXISTS idx_spaces_active_rating ON spaces(rating_avg, id) WHERE is_active;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_tenant_date_from ON bookings(tenant_id, date_from, id);
+CREATE INDEX IF NOT EXISTS idx_bookings_space_date_from ON bookings(space_id, date_from, id);
+
+CREATE INDEX IF NOT EXISTS idx_favorites_user_created ON favorites(user_id, created_at, space_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_spaces_search_vector;
+ALTER TABLE spaces DROP COLUMN IF EXISTS search_vector;
+DROP TEXT SEARCH CONFIGURATION IF EXISTS spacebook;
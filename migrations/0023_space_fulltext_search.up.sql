@@ -0,0 +1,18 @@
+-- Конфигурация полнотекстового поиска. Копия russian: русские слова приводятся
+-- к основе russian_stem, латиница — english_stem. Менять словари можно через
+-- ALTER TEXT SEARCH CONFIGURATION spacebook, после чего пересоздать search_vector.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'spacebook') THEN
+        CREATE TEXT SEARCH CONFIGURATION spacebook (COPY = russian);
+    END IF;
+END $$;
+
+-- Заголовок весит больше описания
+ALTER TABLE spaces ADD COLUMN IF NOT EXISTS search_vector TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('spacebook', COALESCE(title, '')), 'A') ||
+        setweight(to_tsvector('spacebook', COALESCE(description, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_spaces_search_vector ON spaces USING GIN (search_vector);
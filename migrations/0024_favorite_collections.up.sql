@@ -0,0 +1,19 @@
+-- Именованные подборки избранного; share_token даёт доступ на чтение по ссылке
+CREATE TABLE IF NOT EXISTS collections (
+    id          SERIAL PRIMARY KEY,
+    user_id     INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name        VARCHAR(100) NOT NULL,
+    share_token VARCHAR(64) UNIQUE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS collection_spaces (
+    collection_id INTEGER NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    space_id      INTEGER NOT NULL REFERENCES spaces(id) ON DELETE CASCADE,
+    added_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (collection_id, space_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_collection_spaces_space_id ON collection_spaces(space_id);
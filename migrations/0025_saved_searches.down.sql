@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS saved_search_matches;
+DROP TABLE IF EXISTS saved_searches;
+
+DROP INDEX IF EXISTS idx_spaces_listed_at;
+ALTER TABLE spaces DROP COLUMN IF EXISTS listed_at;
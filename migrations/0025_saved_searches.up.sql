@@ -0,0 +1,29 @@
+-- Когда пространство последний раз появилось в каталоге: при создании или возврате из архива
+ALTER TABLE spaces ADD COLUMN IF NOT EXISTS listed_at TIMESTAMPTZ;
+UPDATE spaces SET listed_at = created_at WHERE listed_at IS NULL;
+ALTER TABLE spaces ALTER COLUMN listed_at SET DEFAULT NOW();
+ALTER TABLE spaces ALTER COLUMN listed_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_spaces_listed_at ON spaces(listed_at) WHERE is_active;
+
+-- Сохранённые поиски; checked_at — до какого момента новые пространства уже проверены
+CREATE TABLE IF NOT EXISTS saved_searches (
+    id         SERIAL PRIMARY KEY,
+    user_id    INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name       VARCHAR(100) NOT NULL,
+    filter     JSONB NOT NULL DEFAULT '{}',
+    is_paused  BOOLEAN NOT NULL DEFAULT FALSE,
+    checked_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_searches_user_id ON saved_searches(user_id);
+
+-- О каждом пространстве по поиску уведомляем один раз
+CREATE TABLE IF NOT EXISTS saved_search_matches (
+    saved_search_id INTEGER NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+    space_id        INTEGER NOT NULL REFERENCES spaces(id) ON DELETE CASCADE,
+    matched_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (saved_search_id, space_id)
+);
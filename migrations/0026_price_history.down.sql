@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS price_alert_settings;
+ALTER TABLE favorites DROP COLUMN IF EXISTS notified_price;
+DROP TABLE IF EXISTS space_price_history;
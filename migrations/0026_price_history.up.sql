@@ -0,0 +1,28 @@
+-- История базовой цены пространства: новая строка при каждом изменении цены или валюты
+CREATE TABLE IF NOT EXISTS space_price_history (
+    id         SERIAL PRIMARY KEY,
+    space_id   INTEGER NOT NULL REFERENCES spaces(id) ON DELETE CASCADE,
+    price      INTEGER NOT NULL,
+    currency   CHAR(3) NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_space_price_history_space ON space_price_history(space_id, changed_at);
+
+-- Текущие цены становятся первой точкой истории
+INSERT INTO space_price_history (space_id, price, currency)
+SELECT id, price, currency FROM spaces
+WHERE NOT EXISTS (SELECT 1 FROM space_price_history h WHERE h.space_id = spaces.id);
+
+-- Цена, от которой считается снижение для уведомления: цена на момент
+-- добавления в избранное или последнего уведомления
+ALTER TABLE favorites ADD COLUMN IF NOT EXISTS notified_price INTEGER;
+UPDATE favorites f SET notified_price = s.price FROM spaces s WHERE s.id = f.space_id AND f.notified_price IS NULL;
+
+-- Настройки уведомлений о снижении цены; нет строки — действуют значения по умолчанию
+CREATE TABLE IF NOT EXISTS price_alert_settings (
+    user_id       INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    enabled       BOOLEAN NOT NULL DEFAULT TRUE,
+    threshold_pct INTEGER NOT NULL DEFAULT 10 CHECK (threshold_pct BETWEEN 0 AND 99),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,20 @@
+-- Исходящие события по броням. Строка пишется в той же транзакции, что и изменение брони,
+-- и доставляется диспетчером; до успешной доставки processed_at пуст.
+CREATE TABLE IF NOT EXISTS booking_outbox (
+    id              BIGSERIAL PRIMARY KEY,
+    event_type      VARCHAR(50) NOT NULL,
+    payload         JSONB NOT NULL,
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error      TEXT,
+    failed_at       TIMESTAMPTZ,
+    processed_at    TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Очередь на доставку: только необработанные и не исчерпавшие попытки события
+CREATE INDEX IF NOT EXISTS idx_booking_outbox_due ON booking_outbox(next_attempt_at, id)
+    WHERE processed_at IS NULL AND failed_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_booking_outbox_processed ON booking_outbox(processed_at)
+    WHERE processed_at IS NOT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE booking_status_history
+    DROP COLUMN IF EXISTS refund_pct,
+    DROP COLUMN IF EXISTS refund_amount;
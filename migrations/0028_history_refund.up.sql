@@ -0,0 +1,4 @@
+-- Возврат, назначенный при отмене, хранится и в записи истории об этой отмене
+ALTER TABLE booking_status_history
+    ADD COLUMN IF NOT EXISTS refund_pct    INTEGER,
+    ADD COLUMN IF NOT EXISTS refund_amount INTEGER;
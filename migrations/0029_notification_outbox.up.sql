@@ -0,0 +1,20 @@
+-- Исходящие уведомления пользователям (совпадения сохранённых поисков, снижение цены).
+-- Строка пишется в той же транзакции, что и отметка «уведомлено», поэтому уведомление
+-- не теряется, даже если доставка не удалась; устроена так же, как booking_outbox.
+CREATE TABLE IF NOT EXISTS notification_outbox (
+    id              BIGSERIAL PRIMARY KEY,
+    event_type      VARCHAR(50) NOT NULL,
+    payload         JSONB NOT NULL,
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error      TEXT,
+    failed_at       TIMESTAMPTZ,
+    processed_at    TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_outbox_due ON notification_outbox(next_attempt_at, id)
+    WHERE processed_at IS NULL AND failed_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_notification_outbox_processed ON notification_outbox(processed_at)
+    WHERE processed_at IS NOT NULL;